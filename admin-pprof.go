@@ -0,0 +1,147 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package listener
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"runtime/pprof"
+	"runtime/trace"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// mountPprof - serves profiles the same way `net/http/pprof` does, without registering them on `http.DefaultServeMux` of every application importing this package
+func mountPprof(mux chi.Router) {
+	mux.Get("/debug/pprof", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/debug/pprof/", http.StatusMovedPermanently)
+	})
+	mux.Get("/debug/pprof/", pprofIndex)
+	mux.Get("/debug/pprof/cmdline", pprofCmdline)
+	mux.Get("/debug/pprof/profile", pprofCPU)
+	mux.Get("/debug/pprof/trace", pprofTrace)
+	mux.Get("/debug/pprof/{name}", pprofLookup)
+}
+
+// pprofIndex - lists the available profiles
+func pprofIndex(w http.ResponseWriter, r *http.Request) {
+
+	profiles := pprof.Profiles()
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].Name() < profiles[j].Name()
+	})
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+	fmt.Fprintln(w, "profiles, e.g. `go tool pprof http://<address>/debug/pprof/heap`")
+	for _, p := range profiles {
+		fmt.Fprintf(w, "%s (%d) - %s?debug=1\n", p.Name(), p.Count(), p.Name())
+	}
+	fmt.Fprintln(w, "profile - CPU profile, profile?seconds=30")
+	fmt.Fprintln(w, "trace - execution trace, trace?seconds=5")
+	fmt.Fprintln(w, "cmdline - command line of the process")
+}
+
+// pprofCmdline - responds with the command line of the process, arguments separated by NUL bytes
+func pprofCmdline(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprint(w, strings.Join(os.Args, "\x00"))
+}
+
+// pprofCPU - records a CPU profile for the requested number of seconds
+func pprofCPU(w http.ResponseWriter, r *http.Request) {
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", `attachment; filename="profile"`)
+
+	if err := pprof.StartCPUProfile(w); nil != err {
+		pprofError(w, http.StatusInternalServerError, fmt.Sprintf("could not enable CPU profiling: %s", err))
+		return
+	}
+
+	pprofSleep(r, pprofSeconds(r, 30))
+	pprof.StopCPUProfile()
+}
+
+// pprofTrace - records an execution trace for the requested number of seconds
+func pprofTrace(w http.ResponseWriter, r *http.Request) {
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", `attachment; filename="trace"`)
+
+	if err := trace.Start(w); nil != err {
+		pprofError(w, http.StatusInternalServerError, fmt.Sprintf("could not enable tracing: %s", err))
+		return
+	}
+
+	pprofSleep(r, pprofSeconds(r, 1))
+	trace.Stop()
+}
+
+// pprofLookup - writes the named profile, e.g. heap or goroutine, in text form when `debug` is greater than 0
+func pprofLookup(w http.ResponseWriter, r *http.Request) {
+
+	name := chi.URLParam(r, "name")
+
+	p := pprof.Lookup(name)
+	if nil == p {
+		pprofError(w, http.StatusNotFound, fmt.Sprintf("unknown profile '%s'", name))
+		return
+	}
+
+	debug, _ := strconv.Atoi(r.URL.Query().Get("debug"))
+
+	if debug > 0 {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	} else {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, name))
+	}
+
+	p.WriteTo(w, debug)
+}
+
+// pprofSeconds - returns the duration requested through the `seconds` query parameter
+func pprofSeconds(r *http.Request, fallback int) (d time.Duration) {
+
+	seconds, err := strconv.Atoi(r.URL.Query().Get("seconds"))
+	if nil != err || seconds <= 0 {
+		seconds = fallback
+	}
+
+	return time.Duration(seconds) * time.Second
+}
+
+// pprofSleep - waits for the duration, or until the request is cancelled or times out
+func pprofSleep(r *http.Request, d time.Duration) {
+	select {
+	case <-time.After(d):
+	case <-r.Context().Done():
+	}
+}
+
+// pprofError - responds with a plain text error, clearing the headers set for profiles
+func pprofError(w http.ResponseWriter, code int, msg string) {
+	w.Header().Del("Content-Disposition")
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(code)
+	fmt.Fprintln(w, msg)
+}
@@ -0,0 +1,422 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package listener
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/handletec/listener/rest"
+)
+
+const (
+	// DefaultAdminAddr - the admin listener is only reachable from the same host unless another address is given
+	DefaultAdminAddr = "127.0.0.1"
+
+	// DefaultAdminPort - default port of the admin listener
+	DefaultAdminPort = 9090
+
+	// AdminName - name of the admin listener, used in logs and to match sockets passed through socket activation
+	AdminName = "admin"
+)
+
+// maskedValue - replaces the value of settings that look like secrets
+const maskedValue = "********"
+
+// processStarted - time the process started, reported as the uptime
+var processStarted = time.Now()
+
+// AdminOptions - options of the admin listener
+type AdminOptions struct {
+	TLS     []*TLSConfigBuilder // certificates reported with their expiry, usually the same as `RunOptions.TLS`
+	Pprof   bool                // serve profiles under `/debug/pprof/` for `go tool pprof`
+	Timeout time.Duration       // maximum time to serve a request, long enough to record CPU profiles and traces
+}
+
+// NewAdminOptions - creates new instance of admin options with sane default values
+func NewAdminOptions() (opts *AdminOptions) {
+	opts = new(AdminOptions)
+	opts.Pprof = true
+	opts.Timeout = time.Duration(2 * time.Minute)
+
+	return
+}
+
+// NewAdmin - creates an initialized REST listener serving introspection endpoints for the given listeners: their status, configuration with secrets masked,
+// routes, TLS certificates, runtime stats and pprof profiles. It binds to `DefaultAdminAddr` and `DefaultAdminPort` when no address or port is given.
+// Run it alongside the listeners it reports on, it is not included in its own reports
+func NewAdmin(logger *slog.Logger, address string, port int, ls Listeners, opts *AdminOptions) (l *rest.Listener, err error) {
+
+	if nil == opts {
+		opts = NewAdminOptions()
+	}
+
+	if len(address) == 0 {
+		address = DefaultAdminAddr // introspection must never be exposed on every interface by accident
+	}
+
+	if port == 0 {
+		port = DefaultAdminPort
+	}
+
+	a := &admin{listeners: ls, opts: opts}
+
+	mux := chi.NewRouter()
+	mux.Get("/admin", a.index)
+	mux.Get("/admin/status", a.status)
+	mux.Get("/admin/describe", a.describe)
+	mux.Get("/admin/config", a.config)
+	mux.Get("/admin/routes", a.routes)
+	mux.Get("/admin/tls", a.tls)
+	mux.Get("/admin/runtime", a.runtime)
+
+	if opts.Pprof {
+		mountPprof(mux)
+	}
+
+	config := rest.NewConfig()
+	if opts.Timeout > 0 {
+		config.Timeout = opts.Timeout
+	}
+
+	if err = config.SetRouter(rest.NewChi(mux)); nil != err {
+		return nil, fmt.Errorf("admin: %w", err)
+	}
+
+	l = rest.New()
+	l.SetName(AdminName)
+
+	if err = l.SetConfig(config); nil != err {
+		return nil, fmt.Errorf("admin: %w", err)
+	}
+
+	if err = l.Init(logger, address, port, nil); nil != err {
+		return nil, fmt.Errorf("admin: %w", err)
+	}
+
+	return
+}
+
+// admin - handlers of the admin listener
+type admin struct {
+	listeners Listeners
+	opts      *AdminOptions
+}
+
+// adminStatus - status of a listener as reported by the admin listener
+type adminStatus struct {
+	Name    string    `json:"name"`
+	State   string    `json:"state"`
+	Since   time.Time `json:"since"`
+	Error   string    `json:"error,omitempty"`
+	Address string    `json:"address,omitempty"`
+}
+
+// adminConfig - configuration of a listener as reported by the admin listener
+type adminConfig struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Config any    `json:"config"`
+}
+
+// adminRoutes - routes of a REST listener
+type adminRoutes struct {
+	Name   string       `json:"name"`
+	Routes []rest.Route `json:"routes"`
+}
+
+// adminCertificate - certificate served by a TLS config builder
+type adminCertificate struct {
+	Subject   string    `json:"subject,omitempty"`
+	Issuer    string    `json:"issuer,omitempty"`
+	Serial    string    `json:"serial,omitempty"`
+	DNSNames  []string  `json:"dns_names,omitempty"`
+	IPs       []string  `json:"ips,omitempty"`
+	NotBefore time.Time `json:"not_before"`
+	NotAfter  time.Time `json:"not_after"`
+	ExpiresIn string    `json:"expires_in"`
+	Expired   bool      `json:"expired"`
+	CertFile  string    `json:"cert_file,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// index - lists the endpoints of the admin listener
+func (a *admin) index(w http.ResponseWriter, r *http.Request) {
+
+	endpoints := []string{"/admin/status", "/admin/describe", "/admin/config", "/admin/routes", "/admin/tls", "/admin/runtime"}
+	if a.opts.Pprof {
+		endpoints = append(endpoints, "/debug/pprof/")
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"endpoints": endpoints})
+}
+
+// status - reports the status of every listener, responding with 503 unless all of them are running so it can be used as a probe
+func (a *admin) status(w http.ResponseWriter, r *http.Request) {
+
+	statuses := make([]adminStatus, 0, len(a.listeners))
+	for _, l := range a.listeners {
+		status := l.Status()
+
+		s := adminStatus{
+			Name:  l.Name(),
+			State: status.State.String(),
+			Since: status.Since,
+		}
+
+		if nil != status.Err {
+			s.Error = status.Err.Error()
+		}
+
+		if addr := l.Addr(); nil != addr {
+			s.Address = addr.String()
+		}
+
+		statuses = append(statuses, s)
+	}
+
+	code := http.StatusOK
+	if !a.listeners.Running() {
+		code = http.StatusServiceUnavailable
+	}
+
+	writeJSON(w, code, statuses)
+}
+
+// describe - reports the description of every listener
+func (a *admin) describe(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, a.listeners.Describe())
+}
+
+// config - reports the configuration in use by every listener, with secrets masked
+func (a *admin) config(w http.ResponseWriter, r *http.Request) {
+
+	configs := make([]adminConfig, 0, len(a.listeners))
+	for _, l := range a.listeners {
+		c := adminConfig{
+			Name: l.Name(),
+			Type: fmt.Sprintf("%T", l),
+		}
+
+		if cl, ok := l.(interface{ Config() any }); ok {
+			c.Config = maskValue(reflect.ValueOf(cl.Config()), false, 0)
+		}
+
+		configs = append(configs, c)
+	}
+
+	writeJSON(w, http.StatusOK, configs)
+}
+
+// routes - reports the routes of every REST listener
+func (a *admin) routes(w http.ResponseWriter, r *http.Request) {
+
+	routes := make([]adminRoutes, 0, len(a.listeners))
+	for _, l := range a.listeners {
+		if rl, ok := l.(interface{ Routes() []rest.Route }); ok {
+			routes = append(routes, adminRoutes{Name: l.Name(), Routes: rl.Routes()})
+		}
+	}
+
+	writeJSON(w, http.StatusOK, routes)
+}
+
+// tls - reports the certificates served by the TLS config builders and when they expire
+func (a *admin) tls(w http.ResponseWriter, r *http.Request) {
+
+	now := time.Now()
+
+	certs := make([]adminCertificate, 0, len(a.opts.TLS))
+	for _, t := range a.opts.TLS {
+		if nil == t {
+			continue
+		}
+
+		c := adminCertificate{CertFile: t.certFile}
+
+		leaf, err := t.Certificate()
+		switch {
+		case nil != err:
+			c.Error = err.Error()
+		case nil == leaf:
+			c.Error = "no certificate loaded"
+		default:
+			c.Subject = leaf.Subject.String()
+			c.Issuer = leaf.Issuer.String()
+			c.Serial = leaf.SerialNumber.String()
+			c.DNSNames = leaf.DNSNames
+			for _, ip := range leaf.IPAddresses {
+				c.IPs = append(c.IPs, ip.String())
+			}
+			c.NotBefore = leaf.NotBefore
+			c.NotAfter = leaf.NotAfter
+			c.ExpiresIn = leaf.NotAfter.Sub(now).Truncate(time.Second).String()
+			c.Expired = now.After(leaf.NotAfter)
+		}
+
+		certs = append(certs, c)
+	}
+
+	writeJSON(w, http.StatusOK, certs)
+}
+
+// runtime - reports the Go runtime and memory statistics of the process
+func (a *admin) runtime(w http.ResponseWriter, r *http.Request) {
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	stats := map[string]any{
+		"go_version": runtime.Version(),
+		"os":         runtime.GOOS,
+		"arch":       runtime.GOARCH,
+		"num_cpu":    runtime.NumCPU(),
+		"gomaxprocs": runtime.GOMAXPROCS(0),
+		"goroutines": runtime.NumGoroutine(),
+		"cgo_calls":  runtime.NumCgoCall(),
+		"started":    processStarted,
+		"uptime":     time.Since(processStarted).Truncate(time.Second).String(),
+		"memory": map[string]any{
+			"alloc":          mem.Alloc,
+			"total_alloc":    mem.TotalAlloc,
+			"sys":            mem.Sys,
+			"heap_alloc":     mem.HeapAlloc,
+			"heap_inuse":     mem.HeapInuse,
+			"heap_objects":   mem.HeapObjects,
+			"stack_inuse":    mem.StackInuse,
+			"num_gc":         mem.NumGC,
+			"gc_pause":       time.Duration(mem.PauseTotalNs).String(),
+			"gc_cpu_percent": mem.GCCPUFraction * 100,
+		},
+	}
+
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// writeJSON - writes the value as indented JSON
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+// isSecret - determines if a setting holds a secret by its name
+func isSecret(name string) (secret bool) {
+
+	name = strings.ToLower(name)
+
+	for _, s := range []string{"secret", "password", "passwd", "token", "key", "credential", "authorization", "cookie", "dsn"} {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// maskValue - converts a configuration value into one that can be encoded as JSON, masking settings whose name looks like a secret along with
+// every field and element they hold.
+// Functions and values without exported fields, such as handlers and codecs, are reported by their type only
+func maskValue(v reflect.Value, secret bool, depth int) (out any) {
+
+	if !v.IsValid() {
+		return nil
+	}
+
+	if depth > 8 {
+		return v.Type().String() // guards against cycles
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+
+		if secret {
+			return maskValue(v.Elem(), true, depth+1)
+		}
+	case reflect.String:
+		if secret && v.Len() > 0 {
+			return maskedValue // only text can hold a secret, flags such as `AllowCredentials` are reported as they are
+		}
+	}
+
+	if v.CanInterface() {
+		if s, ok := v.Interface().(fmt.Stringer); ok {
+			return s.String() // durations, file modes, CIDRs
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return maskValue(v.Elem(), false, depth+1)
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return v.Type().String()
+	case reflect.Struct:
+		fields := make(map[string]any)
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if !f.IsExported() {
+				continue
+			}
+
+			fields[f.Name] = maskValue(v.Field(i), secret || isSecret(f.Name), depth+1)
+		}
+
+		if len(fields) == 0 {
+			return v.Type().String()
+		}
+
+		return fields
+	case reflect.Map:
+		entries := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			entries[key] = maskValue(iter.Value(), secret || isSecret(key), depth+1)
+		}
+
+		return entries
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+
+		items := make([]any, v.Len())
+		for i := range items {
+			items[i] = maskValue(v.Index(i), secret, depth+1) // every element of a secret is a secret
+		}
+
+		return items
+	}
+
+	if v.CanInterface() {
+		return v.Interface()
+	}
+
+	return v.Type().String()
+}
@@ -0,0 +1,98 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package listener
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMaskValue(t *testing.T) {
+
+	type credential struct {
+		User  string
+		Value string
+	}
+
+	tests := []struct {
+		name   string
+		config any
+		want   string // JSON of the masked configuration
+	}{
+		{
+			name: "secret by name",
+			config: struct {
+				Address  string
+				Password string
+				APIKey   string
+			}{Address: "127.0.0.1", Password: "hunter2"},
+			want: `{"APIKey":"","Address":"127.0.0.1","Password":"********"}`,
+		},
+		{
+			name:   "secret slice",
+			config: struct{ Tokens []string }{Tokens: []string{"a", "b"}},
+			want:   `{"Tokens":["********","********"]}`,
+		},
+		{
+			name:   "secret array",
+			config: struct{ Keys [2]string }{Keys: [2]string{"a", "b"}},
+			want:   `{"Keys":["********","********"]}`,
+		},
+		{
+			name:   "secret map",
+			config: struct{ Secrets map[string]string }{Secrets: map[string]string{"db": "postgres://user:pass@db"}},
+			want:   `{"Secrets":{"db":"********"}}`,
+		},
+		{
+			name:   "secret map of structs",
+			config: struct{ Credentials map[string]credential }{Credentials: map[string]credential{"db": {User: "app", Value: "pass"}}},
+			want:   `{"Credentials":{"db":{"User":"********","Value":"********"}}}`,
+		},
+		{
+			name:   "secret map key of a plain map",
+			config: struct{ Headers map[string]string }{Headers: map[string]string{"Authorization": "Bearer abc", "Accept": "*/*"}},
+			want:   `{"Headers":{"Accept":"*/*","Authorization":"********"}}`,
+		},
+		{
+			name:   "plain slice",
+			config: struct{ Origins []string }{Origins: []string{"https://app.example.com"}},
+			want:   `{"Origins":["https://app.example.com"]}`,
+		},
+		{
+			name: "values that are not text",
+			config: struct {
+				TokenTTL time.Duration
+				KeyCount int
+			}{TokenTTL: time.Minute, KeyCount: 2},
+			want: `{"KeyCount":2,"TokenTTL":"1m0s"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(maskValue(reflect.ValueOf(tt.config), false, 0))
+			if nil != err {
+				t.Fatal(err)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("maskValue() = %s, expected %s", got, tt.want)
+			}
+		})
+	}
+}
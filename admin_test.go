@@ -0,0 +1,51 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package listener_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/handletec/listener"
+	"github.com/handletec/listener/listenertest"
+	"github.com/handletec/listener/rest"
+)
+
+func TestAdminDescribeInvalidConfig(t *testing.T) {
+
+	// a listener whose configuration `Start` would reject is still described
+	cfg := rest.NewConfig()
+	cfg.RPS = 0
+	cfg.SetRouter(rest.NewRouter("/api"))
+
+	api := rest.New()
+	api.SetName("api")
+	if err := api.SetConfig(cfg); nil != err {
+		t.Fatal(err)
+	}
+
+	admin, err := listener.NewAdmin(nil, "", 0, listener.Listeners{api}, listener.NewAdminOptions())
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	opts := listenertest.NewOptions()
+	opts.InMemory = true
+
+	s := listenertest.Start(t, admin, opts)
+
+	s.Get("/admin/describe").Status(http.StatusOK).BodyContains(`"Throttle(0)"`)
+}
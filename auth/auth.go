@@ -0,0 +1,128 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/textproto"
+)
+
+var (
+	// ErrUnauthenticated - returned when the client could not be authenticated
+	ErrUnauthenticated = errors.New("unauthenticated")
+
+	// ErrForbidden - returned when the principal is not allowed to perform the action on the resource
+	ErrForbidden = errors.New("forbidden")
+)
+
+// Metadata - protocol specific key value pairs sent with a request, such as HTTP headers. Keys are case insensitive
+type Metadata map[string][]string
+
+// Get - returns the first value of the given key, empty if there is none
+func (md Metadata) Get(key string) (value string) {
+	values := md[textproto.CanonicalMIMEHeaderKey(key)]
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+// Request - protocol agnostic description of a request or connection to be authenticated
+type Request struct {
+	Protocol   string               // protocol the request was received on, e.g. `REST`
+	RemoteAddr net.Addr             // address of the client
+	TLS        *tls.ConnectionState // TLS details including client certificates, nil for connections without TLS
+	Metadata   Metadata             // protocol specific details such as HTTP headers
+	Raw        any                  // protocol specific request, e.g. `*http.Request` for REST, for authenticators needing more details
+}
+
+// Authenticator - turns a request or connection into a principal, returning an error wrapping `ErrUnauthenticated` if the client could not be authenticated
+type Authenticator interface {
+	Authenticate(ctx context.Context, req *Request) (p *Principal, err error)
+}
+
+// Authorizer - decides if the principal may perform the action on the resource, returning an error wrapping `ErrForbidden` if it may not
+type Authorizer interface {
+	Authorize(ctx context.Context, p *Principal, action, resource string) (err error)
+}
+
+// AuthenticatorFunc - allows ordinary functions to be used as an `Authenticator`
+type AuthenticatorFunc func(ctx context.Context, req *Request) (p *Principal, err error)
+
+// Authenticate - calls fn(ctx, req)
+func (fn AuthenticatorFunc) Authenticate(ctx context.Context, req *Request) (p *Principal, err error) {
+	return fn(ctx, req)
+}
+
+// AuthorizerFunc - allows ordinary functions to be used as an `Authorizer`
+type AuthorizerFunc func(ctx context.Context, p *Principal, action, resource string) (err error)
+
+// Authorize - calls fn(ctx, p, action, resource)
+func (fn AuthorizerFunc) Authorize(ctx context.Context, p *Principal, action, resource string) (err error) {
+	return fn(ctx, p, action, resource)
+}
+
+// Authenticate - authenticates the request and returns a copy of ctx carrying the principal. Errors always wrap `ErrUnauthenticated`
+func Authenticate(ctx context.Context, authn Authenticator, req *Request) (authCtx context.Context, p *Principal, err error) {
+
+	p, err = authn.Authenticate(ctx, req)
+	if nil != err {
+		if !errors.Is(err, ErrUnauthenticated) {
+			err = fmt.Errorf("%w: %w", ErrUnauthenticated, err)
+		}
+		return ctx, nil, err
+	}
+
+	if nil == p {
+		return ctx, nil, fmt.Errorf("%w: no principal returned", ErrUnauthenticated)
+	}
+
+	return WithPrincipal(ctx, p), p, nil
+}
+
+// Authorize - authorizes the principal stored in ctx for the action on the resource. Errors wrap `ErrUnauthenticated` when ctx carries no principal, otherwise `ErrForbidden`
+func Authorize(ctx context.Context, authz Authorizer, action, resource string) (err error) {
+
+	p, ok := FromContext(ctx)
+	if !ok {
+		return fmt.Errorf("%w: no principal", ErrUnauthenticated)
+	}
+
+	err = authz.Authorize(ctx, p, action, resource)
+	if nil != err && !errors.Is(err, ErrForbidden) {
+		err = fmt.Errorf("%w: %w", ErrForbidden, err)
+	}
+
+	return
+}
+
+// RequireRole - authorizer allowing principals granted any of the roles, regardless of the action and resource
+func RequireRole(roles ...string) (authz Authorizer) {
+	return AuthorizerFunc(func(ctx context.Context, p *Principal, action, resource string) (err error) {
+		for _, role := range roles {
+			if p.HasRole(role) {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("%w: %s requires one of the roles %v", ErrForbidden, resource, roles)
+	})
+}
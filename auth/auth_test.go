@@ -0,0 +1,156 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestAuthenticate(t *testing.T) {
+
+	alice := &Principal{Subject: "alice"}
+
+	tests := []struct {
+		name    string
+		authn   AuthenticatorFunc
+		wantErr error
+	}{
+		{
+			name:  "principal returned",
+			authn: func(ctx context.Context, req *Request) (p *Principal, err error) { return alice, nil },
+		},
+		{
+			name: "error wrapped",
+			authn: func(ctx context.Context, req *Request) (p *Principal, err error) {
+				return nil, errors.New("invalid token")
+			},
+			wantErr: ErrUnauthenticated,
+		},
+		{
+			name:    "error already wrapping ErrUnauthenticated",
+			authn:   func(ctx context.Context, req *Request) (p *Principal, err error) { return nil, ErrUnauthenticated },
+			wantErr: ErrUnauthenticated,
+		},
+		{
+			name:    "no principal",
+			authn:   func(ctx context.Context, req *Request) (p *Principal, err error) { return nil, nil },
+			wantErr: ErrUnauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, p, err := Authenticate(context.Background(), tt.authn, &Request{Protocol: "TCP"})
+			stored, ok := FromContext(ctx)
+
+			if nil != tt.wantErr {
+				if !errors.Is(err, tt.wantErr) || nil != p || ok {
+					t.Errorf("Authenticate() = %v, %v, principal in context %t, expected error %v", p, err, ok, tt.wantErr)
+				}
+				return
+			}
+
+			if nil != err || p != alice || !ok || stored != alice {
+				t.Errorf("Authenticate() = %v, %v, principal in context %v, expected %v", p, err, stored, alice)
+			}
+		})
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+
+	admin := WithPrincipal(context.Background(), &Principal{Subject: "alice", Roles: []string{"reader", "admin"}})
+	reader := WithPrincipal(context.Background(), &Principal{Subject: "bob", Roles: []string{"reader"}})
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		authz   Authorizer
+		wantErr error
+	}{
+		{name: "role granted", ctx: admin, authz: RequireRole("admin")},
+		{name: "any of the roles granted", ctx: reader, authz: RequireRole("admin", "reader")},
+		{name: "role missing", ctx: reader, authz: RequireRole("admin"), wantErr: ErrForbidden},
+		{name: "empty role list forbids everyone", ctx: admin, authz: RequireRole(), wantErr: ErrForbidden},
+		{name: "not authenticated", ctx: context.Background(), authz: RequireRole("reader"), wantErr: ErrUnauthenticated},
+		{name: "nil principal in context", ctx: WithPrincipal(context.Background(), nil), authz: RequireRole("reader"), wantErr: ErrUnauthenticated},
+		{
+			name: "error wrapped",
+			ctx:  admin,
+			authz: AuthorizerFunc(func(ctx context.Context, p *Principal, action, resource string) (err error) {
+				return errors.New("read only")
+			}),
+			wantErr: ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Authorize(tt.ctx, tt.authz, "delete", "/users")
+			if !errors.Is(err, tt.wantErr) || (nil == tt.wantErr && nil != err) {
+				t.Errorf("Authorize() = %v, expected %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPrincipalHasRole(t *testing.T) {
+
+	tests := []struct {
+		name string
+		p    *Principal
+		role string
+		want bool
+	}{
+		{name: "granted", p: &Principal{Roles: []string{"reader", "admin"}}, role: "admin", want: true},
+		{name: "not granted", p: &Principal{Roles: []string{"reader"}}, role: "admin"},
+		{name: "roles are case sensitive", p: &Principal{Roles: []string{"Admin"}}, role: "admin"},
+		{name: "no principal", role: "admin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.p.HasRole(tt.role); got != tt.want {
+				t.Errorf("HasRole(%q) = %t, expected %t", tt.role, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetadataGet(t *testing.T) {
+
+	md := Metadata{"Authorization": {"Bearer abc", "Bearer def"}, "X-Empty": {}}
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{key: "Authorization", want: "Bearer abc"},
+		{key: "authorization", want: "Bearer abc"},
+		{key: "x-empty"},
+		{key: "X-Missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := md.Get(tt.key); got != tt.want {
+				t.Errorf("Get(%q) = %q, expected %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
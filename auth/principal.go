@@ -0,0 +1,57 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package auth
+
+import (
+	"context"
+)
+
+// principalKey - context key the principal is stored under
+type principalKey struct{}
+
+// Principal - identity of an authenticated client
+type Principal struct {
+	Subject string         // unique identifier of the client, such as a user ID or certificate common name
+	Method  string         // how the client was authenticated, e.g. `bearer`, `basic` or `mtls`
+	Roles   []string       // roles granted to the client, used by authorizers
+	Claims  map[string]any // any other details provided by the authenticator
+}
+
+// HasRole - determines if the principal was granted the given role
+func (p *Principal) HasRole(role string) (has bool) {
+	if nil == p {
+		return false
+	}
+
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WithPrincipal - returns a copy of ctx carrying the principal
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext - returns the principal stored in ctx, false if the request was not authenticated
+func FromContext(ctx context.Context) (p *Principal, ok bool) {
+	p, ok = ctx.Value(principalKey{}).(*Principal)
+	return p, ok && nil != p
+}
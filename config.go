@@ -0,0 +1,422 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package listener
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/handletec/listener/rest"
+	"gopkg.in/yaml.v3"
+)
+
+// Config - declarative description of a set of listeners, read from a YAML or JSON file
+type Config struct {
+	Listeners []ListenerConfig `json:"listeners" yaml:"listeners"`
+}
+
+// ListenerConfig - description of a single listener
+type ListenerConfig struct {
+	Name      string           `json:"name" yaml:"name"`           // custom name of the listener, defaults to the protocol name
+	Protocol  string           `json:"protocol" yaml:"protocol"`   // any name accepted by `ParseProto`
+	Address   string           `json:"address" yaml:"address"`     // address to listen on, defaults to all interfaces
+	Port      int              `json:"port" yaml:"port"`           // port to listen on, 0 uses the default port of the protocol and -1 lets the OS pick one
+	TLS       *TLSSettings     `json:"tls" yaml:"tls"`             // serve over TLS when given
+	REST      *RESTSettings    `json:"rest" yaml:"rest"`           // only valid for REST listeners
+	Endpoints []EndpointConfig `json:"endpoints" yaml:"endpoints"` // additional addresses serving the same listener, each with its own TLS settings
+}
+
+// endpointAdder - listeners able to serve on several addresses
+type endpointAdder interface {
+	AddEndpoint(address string, port int, tlsConfig *tls.Config) error
+}
+
+// EndpointConfig - additional address of a listener, for listeners able to serve on several addresses such as REST
+type EndpointConfig struct {
+	Address string       `json:"address" yaml:"address"`
+	Port    int          `json:"port" yaml:"port"`
+	TLS     *TLSSettings `json:"tls" yaml:"tls"` // serve over TLS when given, independent of the TLS settings of the listener
+}
+
+// TLSSettings - certificate, key and CA paths used to build the TLS configuration of a listener through `TLSConfigBuilder`
+type TLSSettings struct {
+	Cert       string        `json:"cert" yaml:"cert"`
+	Key        string        `json:"key" yaml:"key"`
+	CA         []string      `json:"ca" yaml:"ca"`                   // CA files used to verify client certificates
+	CADir      string        `json:"ca_dir" yaml:"ca_dir"`           // directory of .crt and .pem CA files used to verify client certificates
+	SystemCA   bool          `json:"system_ca" yaml:"system_ca"`     // also trust the CA certificates of the OS
+	ClientAuth TLSClientAuth `json:"client_auth" yaml:"client_auth"` // none, request, require, verify or requireverify
+}
+
+// RESTSettings - REST specific settings, any value left unset keeps the default of `rest.NewConfig`
+type RESTSettings struct {
+	RPS             int               `json:"rps" yaml:"rps"`
+	Timeout         Duration          `json:"timeout" yaml:"timeout"`
+	ShutdownTimeout Duration          `json:"shutdown_timeout" yaml:"shutdown_timeout"`
+	Compress        bool              `json:"compress" yaml:"compress"`
+	Headers         map[string]string `json:"headers" yaml:"headers"` // custom headers returned with every response
+	CORS            *CORSSettings     `json:"cors" yaml:"cors"`
+}
+
+// CORSSettings - CORS settings of a REST listener, any list left empty and any value left unset keeps the default of `rest.NewCORS`
+type CORSSettings struct {
+	Origins          []string `json:"origins" yaml:"origins"`
+	Methods          []string `json:"methods" yaml:"methods"`
+	Headers          []string `json:"headers" yaml:"headers"`
+	MaxAge           *int     `json:"max_age" yaml:"max_age"` // nil when not set, unlike 0 which disables caching of preflight requests
+	AllowCredentials *bool    `json:"allow_credentials" yaml:"allow_credentials"`
+	Debug            *bool    `json:"debug" yaml:"debug"`
+}
+
+// Duration - duration written in configuration files as a string such as "15s" or "1m30s"
+type Duration time.Duration
+
+// MarshalText - encodes the duration as a string
+func (d Duration) MarshalText() (text []byte, err error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// UnmarshalText - decodes the duration from a string
+func (d *Duration) UnmarshalText(text []byte) (err error) {
+	v, err := time.ParseDuration(string(text))
+	if nil != err {
+		return fmt.Errorf("parse duration: %w", err)
+	}
+
+	*d = Duration(v)
+	return
+}
+
+// LoadConfig - reads the configuration file at path and returns its listeners initialized and ready to start, along with the TLS config builders
+// serving them, see `Build`. The builders are returned rather than hidden inside the listeners because each one watches its certificate files
+// until it is closed, and `Run` only reloads the certificates of the builders given in `RunOptions.TLS`: pass them there and close them once
+// the listeners stopped. REST listeners still need a router, set it with `SetRouter` after finding the listener with `Listeners.Get`
+func LoadConfig(path string) (ls Listeners, builders []*TLSConfigBuilder, err error) {
+
+	cfg, err := ReadConfig(path)
+	if nil != err {
+		return nil, nil, err
+	}
+
+	return cfg.Build(nil)
+}
+
+// ReadConfig - reads a YAML (.yaml or .yml) or JSON (.json) configuration file, unknown fields are rejected
+func ReadConfig(path string) (cfg *Config, err error) {
+
+	data, err := os.ReadFile(path)
+	if nil != err {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+
+	cfg = new(Config)
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(cfg)
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(cfg)
+	default:
+		return nil, fmt.Errorf("read config '%s': unsupported format, must be .yaml, .yml or .json", path)
+	}
+
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("read config '%s': file is empty", path)
+	}
+
+	if nil != err {
+		return nil, fmt.Errorf("read config '%s': %w", path, err)
+	}
+
+	return
+}
+
+// Build - creates and initializes every listener in the configuration, logging to logger (each listener creates its own logger if nil).
+// The TLS config builders are returned so they can be reloaded through `RunOptions.TLS` and have their expiry reported through `AdminOptions.TLS`,
+// listeners given the same TLS settings share a single builder. Close the builders once the listeners stopped, to stop watching the certificate files.
+// Errors of all listeners are reported together
+func (cfg *Config) Build(logger *slog.Logger) (ls Listeners, builders []*TLSConfigBuilder, err error) {
+
+	if len(cfg.Listeners) == 0 {
+		return nil, nil, errors.New("config build: no listeners configured")
+	}
+
+	var errs []error
+	names := make(map[string]int)
+	tb := &tlsBuilders{bySettings: make(map[*TLSSettings]*TLSConfigBuilder)}
+
+	for i := range cfg.Listeners {
+		lc := &cfg.Listeners[i]
+
+		l, e := lc.build(logger, tb)
+		if nil != e {
+			errs = append(errs, fmt.Errorf("listener %d (%s): %w", i, lc.label(), e))
+			continue
+		}
+
+		// names identify listeners in logs and sockets passed through socket activation, they must be unique
+		if j, exist := names[l.Name()]; exist {
+			errs = append(errs, fmt.Errorf("listener %d (%s): name %s is already used by listener %d, set a unique name", i, lc.label(), l.Name(), j))
+			continue
+		}
+
+		names[l.Name()] = i
+		ls.Add(l)
+	}
+
+	if len(errs) > 0 {
+		for _, builder := range tb.list {
+			builder.Close()
+		}
+
+		return nil, nil, fmt.Errorf("config build: %w", errors.Join(errs...))
+	}
+
+	return ls, tb.list, nil
+}
+
+// build - creates and initializes the listener described by this configuration, adding the TLS config builder of every TLS endpoint to tb
+func (lc *ListenerConfig) build(logger *slog.Logger, tb *tlsBuilders) (l Listener, err error) {
+
+	proto := ParseProto(lc.Protocol)
+	if !proto.IsValid() {
+		return nil, fmt.Errorf("unknown protocol '%s'", lc.Protocol)
+	}
+
+	if l, err = proto.Listener(); nil != err {
+		return nil, err
+	}
+
+	if len(lc.Name) > 0 {
+		named, ok := l.(interface{ SetName(name string) })
+		if !ok {
+			return nil, fmt.Errorf("protocol %s does not support custom names", proto)
+		}
+
+		named.SetName(lc.Name)
+	}
+
+	if nil != lc.REST {
+		restListener, ok := l.(*rest.Listener)
+		if !ok {
+			return nil, fmt.Errorf("rest settings given for a %s listener", proto)
+		}
+
+		restConfig, header, e := lc.REST.config()
+		if nil != e {
+			return nil, fmt.Errorf("rest: %w", e)
+		}
+
+		if err = restListener.SetConfig(restConfig); nil != err {
+			return nil, fmt.Errorf("rest: %w", err)
+		}
+
+		restListener.SetCustomHeaders(header)
+	}
+
+	endpoints, ok := l.(endpointAdder)
+	if len(lc.Endpoints) > 0 && !ok {
+		return nil, fmt.Errorf("protocol %s does not support additional endpoints", proto)
+	}
+
+	tlsConfig, err := tb.serverConfig(lc.TLS)
+	if nil != err {
+		return nil, fmt.Errorf("tls: %w", err)
+	}
+
+	if err = l.Init(logger, lc.Address, lc.Port, tlsConfig); nil != err {
+		return nil, err
+	}
+
+	for i, ep := range lc.Endpoints {
+		if tlsConfig, err = tb.serverConfig(ep.TLS); nil != err {
+			return nil, fmt.Errorf("endpoint %d: tls: %w", i, err)
+		}
+
+		if err = endpoints.AddEndpoint(ep.Address, ep.Port, tlsConfig); nil != err {
+			return nil, fmt.Errorf("endpoint %d: %w", i, err)
+		}
+	}
+
+	return
+}
+
+// label - name used to identify this listener in errors
+func (lc *ListenerConfig) label() (str string) {
+	if len(lc.Name) > 0 {
+		return lc.Name
+	}
+
+	return lc.Protocol
+}
+
+// tlsBuilders - TLS config builders created while building listeners, in the order they were created
+type tlsBuilders struct {
+	list       []*TLSConfigBuilder
+	bySettings map[*TLSSettings]*TLSConfigBuilder // listeners given the same settings, such as those read from the environment, share their builder
+}
+
+// serverConfig - returns the server TLS configuration of the settings, creating their builder on first use. Nil settings serve without TLS
+func (tb *tlsBuilders) serverConfig(s *TLSSettings) (tlsConfig *tls.Config, err error) {
+
+	if nil == s {
+		return nil, nil
+	}
+
+	builder, ok := tb.bySettings[s]
+	if !ok {
+		if builder, err = s.builder(); nil != err {
+			return nil, err
+		}
+
+		tb.bySettings[s] = builder
+		tb.list = append(tb.list, builder)
+	}
+
+	return builder.ForServer(), nil
+}
+
+// builder - creates the TLS config builder, loading the certificate and CA files up front so errors are reported before the listener starts
+func (s *TLSSettings) builder() (t *TLSConfigBuilder, err error) {
+
+	if len(s.Cert) == 0 || len(s.Key) == 0 {
+		return nil, errors.New("cert and key are required")
+	}
+
+	switch s.ClientAuth {
+	case TLSClientAuthVerify, TLSClientAuthRequireVerify:
+		if len(s.CA) == 0 && len(s.CADir) == 0 && !s.SystemCA {
+			return nil, fmt.Errorf("client auth %s needs ca, ca_dir or system_ca to verify client certificates", s.ClientAuth)
+		}
+	}
+
+	if t, err = NewTLSConfigBuilder(s.SystemCA); nil != err {
+		return nil, err
+	}
+
+	if err = t.SetCertKeyFile(s.Cert, s.Key); nil != err {
+		return nil, err
+	}
+
+	// the certificate is loaded here as `ForServer` panics on an invalid certificate
+	if err = t.Reload(); nil != err {
+		return nil, err
+	}
+
+	for _, ca := range s.CA {
+		if err = t.AddCAFile(ca); nil != err {
+			return nil, err
+		}
+	}
+
+	if err = t.AddCADir(s.CADir); nil != err {
+		return nil, err
+	}
+
+	t.SetClientAuth(s.ClientAuth)
+
+	return
+}
+
+// config - creates the REST configuration and custom headers, the headers are nil if none are given
+func (s *RESTSettings) config() (cfg *rest.Config, header *rest.Header, err error) {
+
+	if s.RPS < 0 {
+		return nil, nil, fmt.Errorf("invalid rps %d", s.RPS)
+	}
+
+	if s.Timeout < 0 || s.ShutdownTimeout < 0 {
+		return nil, nil, errors.New("timeouts cannot be negative")
+	}
+
+	cfg = rest.NewConfig()
+
+	if s.RPS > 0 {
+		cfg.RPS = s.RPS
+	}
+
+	if s.Timeout > 0 {
+		cfg.Timeout = time.Duration(s.Timeout)
+	}
+
+	if s.ShutdownTimeout > 0 {
+		cfg.ShutdownTimeout = time.Duration(s.ShutdownTimeout)
+	}
+
+	cfg.EnableCompress(s.Compress)
+
+	if nil != s.CORS {
+		cfg.SetCORS(s.CORS.cors())
+	}
+
+	if len(s.Headers) > 0 {
+		header = rest.NewHeader()
+		for k, v := range s.Headers {
+			header.Add(k, v)
+		}
+	}
+
+	return
+}
+
+// cors - creates the CORS configuration, starting from the defaults of `rest.NewCORS`
+func (s *CORSSettings) cors() (c *rest.CORS) {
+	c = rest.NewCORS()
+	s.apply(c)
+
+	return
+}
+
+// apply - copies the settings that were set onto c, leaving the others unchanged
+func (s *CORSSettings) apply(c *rest.CORS) {
+
+	if len(s.Origins) > 0 {
+		c.SetOrigins(s.Origins)
+	}
+
+	if len(s.Methods) > 0 {
+		c.SetMethods(s.Methods)
+	}
+
+	if len(s.Headers) > 0 {
+		c.SetHeaders(s.Headers)
+	}
+
+	if nil != s.MaxAge {
+		c.MaxAge = *s.MaxAge
+	}
+
+	if nil != s.AllowCredentials {
+		c.AllowCredentials = *s.AllowCredentials
+	}
+
+	if nil != s.Debug {
+		c.Debug = *s.Debug
+	}
+}
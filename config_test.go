@@ -0,0 +1,203 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package listener
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/handletec/listener/rest"
+)
+
+// writeCert - writes a self-signed certificate for localhost and its key to dir, returning their paths
+func writeCert(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	certFile = filepath.Join(dir, "app.crt")
+	keyFile = filepath.Join(dir, "app.key")
+
+	if err = os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); nil != err {
+		t.Fatal(err)
+	}
+
+	if err = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); nil != err {
+		t.Fatal(err)
+	}
+
+	return
+}
+
+func TestLoadConfig(t *testing.T) {
+
+	dir := t.TempDir()
+	certFile, keyFile := writeCert(t, dir)
+	tlsSettings := "\n    tls:\n      cert: " + certFile + "\n      key: " + keyFile
+
+	tests := []struct {
+		name         string
+		content      string
+		wantErr      string
+		wantNames    []string
+		wantBuilders int
+	}{
+		{
+			name:         "plain text",
+			content:      "listeners:\n  - protocol: rest\n    port: -1\n",
+			wantNames:    []string{"REST"},
+			wantBuilders: 0,
+		},
+		{
+			name:         "TLS listeners",
+			content:      "listeners:\n  - protocol: rest\n    port: -1" + tlsSettings + "\n  - protocol: tcp\n    port: -1" + tlsSettings + "\n",
+			wantNames:    []string{"REST", "TCP"},
+			wantBuilders: 2,
+		},
+		{
+			name:    "invalid listener",
+			content: "listeners:\n  - protocol: rest\n    port: -1" + tlsSettings + "\n  - protocol: ftp\n",
+			wantErr: "unknown protocol 'ftp'",
+		},
+		{
+			name:    "unknown field",
+			content: "listeners:\n  - protocol: rest\n    prot: 80\n",
+			wantErr: "field prot not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "listeners.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); nil != err {
+				t.Fatal(err)
+			}
+
+			ls, builders, err := LoadConfig(path)
+			defer func() {
+				for _, b := range builders {
+					b.Close()
+				}
+			}()
+
+			if len(tt.wantErr) > 0 {
+				if nil == err || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadConfig() = %v, want error containing %q", err, tt.wantErr)
+				}
+
+				if nil != ls || nil != builders {
+					t.Errorf("LoadConfig() returned %d listeners and %d builders along with an error", len(ls), len(builders))
+				}
+				return
+			}
+
+			if nil != err {
+				t.Fatalf("LoadConfig(): %v", err)
+			}
+
+			var names []string
+			for _, l := range ls {
+				names = append(names, l.Name())
+			}
+
+			if strings.Join(names, ",") != strings.Join(tt.wantNames, ",") {
+				t.Errorf("listeners = %v, want %v", names, tt.wantNames)
+			}
+
+			if len(builders) != tt.wantBuilders {
+				t.Errorf("%d TLS config builders returned, want %d", len(builders), tt.wantBuilders)
+			}
+		})
+	}
+}
+
+func TestCORSSettings(t *testing.T) {
+
+	// defaults differing from the zero values, so settings left out can be told apart from settings set to zero
+	newDefaults := func() (c *rest.CORS) {
+		c = rest.NewCORS()
+		c.MaxAge = 600
+		c.AllowCredentials = true
+
+		return
+	}
+	defaultOrigins := strings.Join(newDefaults().AllowedOrigins, ",")
+
+	tests := []struct {
+		name     string
+		cors     string
+		wantAge  int
+		wantCred bool
+		wantOrig string
+	}{
+		{name: "only origins keeps the other defaults", cors: "origins: [https://app.example.com]", wantAge: 600, wantCred: true, wantOrig: "https://app.example.com"},
+		{name: "values set to zero", cors: "max_age: 0\n        allow_credentials: false", wantAge: 0, wantCred: false, wantOrig: defaultOrigins},
+		{name: "values set", cors: "max_age: 300", wantAge: 300, wantCred: true, wantOrig: defaultOrigins},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "listeners.yaml")
+			content := "listeners:\n  - protocol: rest\n    rest:\n      cors:\n        " + tt.cors + "\n"
+			if err := os.WriteFile(path, []byte(content), 0o600); nil != err {
+				t.Fatal(err)
+			}
+
+			cfg, err := ReadConfig(path)
+			if nil != err {
+				t.Fatal(err)
+			}
+
+			c := newDefaults()
+			cfg.Listeners[0].REST.CORS.apply(c)
+
+			if c.MaxAge != tt.wantAge || c.AllowCredentials != tt.wantCred || strings.Join(c.AllowedOrigins, ",") != tt.wantOrig {
+				t.Errorf("CORS = max age %d, credentials %t, origins %v, expected %d, %t, %s", c.MaxAge, c.AllowCredentials, c.AllowedOrigins, tt.wantAge, tt.wantCred, tt.wantOrig)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package connlimit
+
+const (
+	// DefaultMaxConns - default maximum number of open connections of a listener
+	DefaultMaxConns = 10000
+
+	// DefaultMaxConnsPerIP - default maximum number of open connections from a single client
+	DefaultMaxConnsPerIP = 100
+)
+
+// Config - connection limits applied when connections are accepted, before any TLS handshake or request parsing takes place
+type Config struct {
+	MaxConns      int // maximum number of open connections, accepting pauses until a connection closes once reached. 0 is unlimited
+	MaxConnsPerIP int // maximum number of open connections from a single IP address, further connections are closed as soon as they are accepted. 0 is unlimited, and required with the PROXY protocol
+}
+
+// NewConfig - creates new instance of config
+func NewConfig() (cfg *Config) {
+	cfg = new(Config)
+
+	// set default configuration
+	cfg.MaxConns = DefaultMaxConns
+	cfg.MaxConnsPerIP = DefaultMaxConnsPerIP
+
+	return
+}
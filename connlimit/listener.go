@@ -0,0 +1,206 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package connlimit
+
+import (
+	"log/slog"
+	"net"
+	"net/netip"
+	"sync"
+)
+
+// Listener - wraps a listener to cap the number of open connections, in total and from each client IP address.
+// Wrap the listener before TLS, so connections over the limits never reach the handshake
+type Listener struct {
+	net.Listener
+	maxConns      int
+	maxConnsPerIP int
+	logger        *slog.Logger
+	slots         chan struct{} // holds a value for every open connection, nil if the total is unlimited
+	perIP         map[netip.Addr]int
+	open          int
+	limited       bool // total limit was reached, logged once until accepting resumes
+	done          chan struct{}
+	closeOnce     sync.Once
+	mu            sync.Mutex // protects perIP, open and limited
+}
+
+// NewListener - create new instance of a listener limiting the connections accepted by ln, decisions are logged through logger
+func NewListener(ln net.Listener, config *Config, logger *slog.Logger) (cl *Listener) {
+	cl = new(Listener)
+	cl.Listener = ln
+	cl.maxConns = config.MaxConns
+	cl.maxConnsPerIP = config.MaxConnsPerIP
+	cl.perIP = make(map[netip.Addr]int)
+	cl.done = make(chan struct{})
+
+	if nil == logger {
+		logger = slog.Default()
+	}
+
+	cl.logger = logger
+
+	if cl.maxConns > 0 {
+		cl.slots = make(chan struct{}, cl.maxConns)
+	}
+
+	return
+}
+
+// Accept - waits for the next connection within the limits. Once the total limit is reached, accepting pauses until a connection closes,
+// leaving new clients waiting in the backlog of the OS. Connections from a client over its limit are closed straight away
+func (cl *Listener) Accept() (conn net.Conn, err error) {
+
+	for {
+		if err = cl.waitSlot(); nil != err {
+			return nil, err
+		}
+
+		conn, err = cl.Listener.Accept()
+		if nil != err {
+			cl.releaseSlot()
+			return nil, err
+		}
+
+		ip, ok := clientIP(conn.RemoteAddr())
+
+		cl.mu.Lock()
+		if ok && cl.maxConnsPerIP > 0 && cl.perIP[ip] >= cl.maxConnsPerIP {
+			cl.mu.Unlock()
+
+			cl.logger.Debug("connection rejected", "remote", conn.RemoteAddr().String(), "reason", "per IP connection limit reached", "limit", cl.maxConnsPerIP)
+
+			conn.Close()
+			cl.releaseSlot()
+			continue
+		}
+
+		if ok {
+			cl.perIP[ip]++
+		}
+		cl.open++
+		cl.mu.Unlock()
+
+		c := new(Conn)
+		c.Conn = conn
+		c.release = func() {
+			cl.release(ip, ok)
+		}
+
+		return c, nil
+	}
+}
+
+// Close - stops accepting connections, connections already accepted are left open
+func (cl *Listener) Close() (err error) {
+	cl.closeOnce.Do(func() {
+		close(cl.done)
+	})
+
+	return cl.Listener.Close()
+}
+
+// Conns - returns the number of open connections
+func (cl *Listener) Conns() (n int) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	return cl.open
+}
+
+// waitSlot - waits until the total number of open connections is below the limit
+func (cl *Listener) waitSlot() (err error) {
+
+	if nil == cl.slots {
+		return
+	}
+
+	select {
+	case cl.slots <- struct{}{}:
+		cl.mu.Lock()
+		cl.limited = false
+		cl.mu.Unlock()
+
+		return
+	default:
+	}
+
+	cl.mu.Lock()
+	if !cl.limited {
+		cl.limited = true
+		cl.logger.Warn("connection limit reached, accepting paused", "limit", cl.maxConns)
+	}
+	cl.mu.Unlock()
+
+	select {
+	case cl.slots <- struct{}{}:
+		return
+	case <-cl.done:
+		return net.ErrClosed
+	}
+}
+
+// releaseSlot - frees the slot taken by a connection
+func (cl *Listener) releaseSlot() {
+	if nil != cl.slots {
+		<-cl.slots
+	}
+}
+
+// release - frees the slot and per IP count of a closed connection
+func (cl *Listener) release(ip netip.Addr, counted bool) {
+
+	cl.mu.Lock()
+	if counted {
+		if cl.perIP[ip] <= 1 {
+			delete(cl.perIP, ip)
+		} else {
+			cl.perIP[ip]--
+		}
+	}
+	cl.open--
+	cl.mu.Unlock()
+
+	cl.releaseSlot()
+}
+
+// clientIP - returns the IP address connections are counted against, Unix sockets have none
+func clientIP(addr net.Addr) (ip netip.Addr, ok bool) {
+
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return ip, false
+	}
+
+	ip, ok = netip.AddrFromSlice(tcpAddr.IP)
+
+	return ip.Unmap(), ok // IPv4 clients of a dual stack socket are reported as IPv4 mapped IPv6 addresses
+}
+
+// Conn - connection counted against the limits until it is closed
+type Conn struct {
+	net.Conn
+	once    sync.Once
+	release func()
+}
+
+// Close - closes the connection, freeing its place within the limits
+func (c *Conn) Close() (err error) {
+	err = c.Conn.Close()
+	c.once.Do(c.release)
+
+	return
+}
@@ -0,0 +1,272 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package connlimit
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/handletec/listener/memnet"
+)
+
+// queueListener - accepts the server end of the queued connections, until it is closed
+type queueListener struct {
+	conns chan net.Conn
+	done  chan struct{}
+	once  sync.Once
+}
+
+func newQueueListener() (ql *queueListener) {
+	ql = new(queueListener)
+	ql.conns = make(chan net.Conn, 16)
+	ql.done = make(chan struct{})
+
+	return
+}
+
+// dial - queues a connection from remote, returning the client end
+func (ql *queueListener) dial(remote net.Addr) (client net.Conn) {
+	server, client := memnet.Pipe(memnet.Addr("server"), remote)
+	ql.conns <- server
+
+	return
+}
+
+func (ql *queueListener) Accept() (conn net.Conn, err error) {
+
+	// connections queued before closing are accepted first
+	select {
+	case conn = <-ql.conns:
+		return conn, nil
+	default:
+	}
+
+	select {
+	case conn = <-ql.conns:
+		return conn, nil
+	case <-ql.done:
+		return nil, net.ErrClosed
+	}
+}
+
+func (ql *queueListener) Close() (err error) {
+	ql.once.Do(func() {
+		close(ql.done)
+	})
+
+	return
+}
+
+func (ql *queueListener) Addr() (addr net.Addr) {
+	return memnet.Addr("server")
+}
+
+// tcpAddr - TCP address of a client
+func tcpAddr(ip string) (addr net.Addr) {
+	return &net.TCPAddr{IP: net.ParseIP(ip), Port: 40000}
+}
+
+// closed - determines if the server end of client was closed
+func closed(client net.Conn) (ok bool) {
+	client.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	_, err := client.Read(make([]byte, 1))
+
+	return errors.Is(err, io.EOF)
+}
+
+func TestListenerPerIP(t *testing.T) {
+
+	unix := &net.UnixAddr{Name: "@", Net: "unix"}
+
+	tests := []struct {
+		name         string
+		maxConnsIP   int
+		remotes      []net.Addr
+		wantRejected []bool // whether the connection from each remote is closed by the listener
+	}{
+		{
+			name:         "limit per IP address",
+			maxConnsIP:   2,
+			remotes:      []net.Addr{tcpAddr("192.0.2.1"), tcpAddr("192.0.2.1"), tcpAddr("192.0.2.1"), tcpAddr("192.0.2.2")},
+			wantRejected: []bool{false, false, true, false},
+		},
+		{
+			name:         "IPv4 mapped IPv6 counted as IPv4",
+			maxConnsIP:   1,
+			remotes:      []net.Addr{tcpAddr("192.0.2.1"), tcpAddr("::ffff:192.0.2.1")},
+			wantRejected: []bool{false, true},
+		},
+		{
+			name:         "unix sockets have no address",
+			maxConnsIP:   1,
+			remotes:      []net.Addr{unix, unix, unix},
+			wantRejected: []bool{false, false, false},
+		},
+		{
+			name:         "unlimited",
+			remotes:      []net.Addr{tcpAddr("192.0.2.1"), tcpAddr("192.0.2.1"), tcpAddr("192.0.2.1")},
+			wantRejected: []bool{false, false, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ql := newQueueListener()
+			cl := NewListener(ql, &Config{MaxConnsPerIP: tt.maxConnsIP}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+			defer cl.Close()
+
+			var clients []net.Conn
+			for _, remote := range tt.remotes {
+				clients = append(clients, ql.dial(remote))
+			}
+			ql.Close() // Accept returns once every queued connection was handled
+
+			accepted := 0
+			for {
+				conn, err := cl.Accept()
+				if nil != err {
+					break
+				}
+				defer conn.Close()
+				accepted++
+			}
+
+			expected := 0
+			for i, client := range clients {
+				if !tt.wantRejected[i] {
+					expected++
+				}
+
+				if got := closed(client); got != tt.wantRejected[i] {
+					t.Errorf("connection %d from %s closed = %t, expected %t", i, tt.remotes[i], got, tt.wantRejected[i])
+				}
+			}
+
+			if accepted != expected || cl.Conns() != expected {
+				t.Errorf("%d connections accepted, %d open, expected %d", accepted, cl.Conns(), expected)
+			}
+		})
+	}
+}
+
+func TestListenerRelease(t *testing.T) {
+
+	ql := newQueueListener()
+	cl := NewListener(ql, &Config{MaxConns: 2, MaxConnsPerIP: 1}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	defer cl.Close()
+
+	ql.dial(tcpAddr("192.0.2.1"))
+	first, err := cl.Accept()
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	// closing twice must free a single place, or the limits would let more connections through than configured
+	first.Close()
+	first.Close()
+
+	if n := cl.Conns(); n != 0 {
+		t.Fatalf("%d connections open once closed, expected 0", n)
+	}
+
+	for _, ip := range []string{"192.0.2.1", "192.0.2.2"} {
+		ql.dial(tcpAddr(ip))
+		conn, err := cl.Accept()
+		if nil != err {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+	}
+
+	if n := cl.Conns(); n != 2 {
+		t.Errorf("%d connections open, expected 2", n)
+	}
+
+	if n := len(cl.slots); n != 2 {
+		t.Errorf("%d slots taken, expected 2", n)
+	}
+}
+
+func TestListenerPausesAtLimit(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		resume func(cl *Listener, first net.Conn) // resumes the pending Accept
+		want   error
+	}{
+		{
+			name:   "connection closed",
+			resume: func(cl *Listener, first net.Conn) { first.Close() },
+		},
+		{
+			name:   "listener closed",
+			resume: func(cl *Listener, first net.Conn) { cl.Close() },
+			want:   net.ErrClosed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ql := newQueueListener()
+			cl := NewListener(ql, &Config{MaxConns: 1}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+			defer cl.Close()
+
+			ql.dial(tcpAddr("192.0.2.1"))
+			first, err := cl.Accept()
+			if nil != err {
+				t.Fatal(err)
+			}
+			defer first.Close()
+
+			ql.dial(tcpAddr("192.0.2.2"))
+
+			type result struct {
+				conn net.Conn
+				err  error
+			}
+			results := make(chan result, 1)
+			go func() {
+				conn, err := cl.Accept()
+				results <- result{conn, err}
+			}()
+
+			select {
+			case r := <-results:
+				t.Fatalf("Accept() = %v, %v at the limit, expected it to wait", r.conn, r.err)
+			case <-time.After(50 * time.Millisecond):
+			}
+
+			tt.resume(cl, first)
+
+			select {
+			case r := <-results:
+				if !errors.Is(r.err, tt.want) {
+					t.Fatalf("Accept() error = %v, expected %v", r.err, tt.want)
+				}
+				if nil != r.conn {
+					r.conn.Close()
+				}
+			case <-time.After(time.Second):
+				t.Fatal("Accept() still waiting, expected it to resume")
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package listener
+
+import (
+	"fmt"
+
+	"github.com/handletec/listener/describe"
+)
+
+// Description - JSON serializable description of how a listener is set up: protocol, address, TLS settings, middleware stack, CORS policy and routes
+type Description = describe.Description
+
+// Describer - optional interface for listeners able to describe how they are set up
+type Describer interface {
+	Describe() Description
+}
+
+// Describe - describes every listener in the order they were added, e.g. to log at startup or to compare in tests to catch configuration drift.
+// Listeners not implementing `Describer` are described by their name, type and address only
+func (ls Listeners) Describe() (descriptions []Description) {
+
+	descriptions = make([]Description, 0, len(ls))
+	for _, l := range ls {
+		if d, ok := l.(Describer); ok {
+			descriptions = append(descriptions, d.Describe())
+			continue
+		}
+
+		d := Description{
+			Name:     l.Name(),
+			Protocol: fmt.Sprintf("%T", l),
+		}
+
+		if addr := l.Addr(); nil != addr {
+			d.Address = addr.String()
+		}
+
+		descriptions = append(descriptions, d)
+	}
+
+	return
+}
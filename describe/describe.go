@@ -0,0 +1,104 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package describe
+
+import (
+	"crypto/tls"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// Description - JSON serializable description of how a listener is set up, suitable to log at startup or to compare in tests
+type Description struct {
+	Name       string     `json:"name"`
+	Protocol   string     `json:"protocol"`
+	Address    string     `json:"address"` // address the listener is bound to once started, otherwise the configured address
+	TLS        TLS        `json:"tls"`
+	Endpoints  []Endpoint `json:"endpoints,omitempty"`  // every endpoint of listeners serving more than one, the first one matches `Address` and `TLS`
+	Middleware []string   `json:"middleware,omitempty"` // middlewares run for every request, in the order they run
+	CORS       *CORS      `json:"cors,omitempty"`
+	Routes     []Route    `json:"routes,omitempty"`
+}
+
+// TLS - TLS settings of a listener or endpoint
+type TLS struct {
+	Enabled    bool   `json:"enabled"`
+	ClientAuth string `json:"client_auth,omitempty"` // none, request, require, verify or requireverify
+	MinVersion string `json:"min_version,omitempty"` // lowest TLS version accepted, `default` leaves it to the Go runtime
+}
+
+// Endpoint - address and TLS settings of a single endpoint
+type Endpoint struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	TLS     TLS    `json:"tls"`
+}
+
+// CORS - cross-origin resource sharing policy
+type CORS struct {
+	Origins          []string `json:"origins"`
+	Methods          []string `json:"methods"`
+	Headers          []string `json:"headers"`
+	MaxAge           int      `json:"max_age"`
+	AllowCredentials bool     `json:"allow_credentials"`
+}
+
+// Route - method and pattern of a registered handler
+type Route struct {
+	Method  string `json:"method"`
+	Pattern string `json:"pattern"`
+}
+
+// TLSOf - describes the TLS configuration, disabled unless it is able to serve certificates
+func TLSOf(cfg *tls.Config) (t TLS) {
+
+	if nil == cfg || (len(cfg.Certificates) == 0 && nil == cfg.GetCertificate && nil == cfg.GetConfigForClient) {
+		return
+	}
+
+	t.Enabled = true
+
+	switch cfg.ClientAuth {
+	case tls.RequestClientCert:
+		t.ClientAuth = "request"
+	case tls.RequireAnyClientCert:
+		t.ClientAuth = "require"
+	case tls.VerifyClientCertIfGiven:
+		t.ClientAuth = "verify"
+	case tls.RequireAndVerifyClientCert:
+		t.ClientAuth = "requireverify"
+	default:
+		t.ClientAuth = "none"
+	}
+
+	t.MinVersion = "default"
+	if cfg.MinVersion != 0 {
+		t.MinVersion = tls.VersionName(cfg.MinVersion)
+	}
+
+	return
+}
+
+// Address - returns the bound address if there is one, otherwise the configured address and port
+func Address(bound net.Addr, address string, port int) (str string) {
+
+	if nil != bound {
+		return bound.String()
+	}
+
+	return net.JoinHostPort(strings.Trim(address, "[]"), strconv.Itoa(port))
+}
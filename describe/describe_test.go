@@ -0,0 +1,83 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package describe
+
+import (
+	"crypto/tls"
+	"net"
+	"testing"
+)
+
+func TestTLSOf(t *testing.T) {
+
+	cert := []tls.Certificate{{}}
+
+	tests := []struct {
+		name string
+		cfg  *tls.Config
+		want TLS
+	}{
+		{name: "no configuration", want: TLS{}},
+		{name: "no certificate", cfg: &tls.Config{MinVersion: tls.VersionTLS13}, want: TLS{}},
+		{name: "certificates", cfg: &tls.Config{Certificates: cert}, want: TLS{Enabled: true, ClientAuth: "none", MinVersion: "default"}},
+		{
+			name: "certificate callback",
+			cfg:  &tls.Config{GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) { return nil, nil }, MinVersion: tls.VersionTLS12},
+			want: TLS{Enabled: true, ClientAuth: "none", MinVersion: "TLS 1.2"},
+		},
+		{
+			name: "configuration callback",
+			cfg:  &tls.Config{GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) { return nil, nil }, ClientAuth: tls.RequestClientCert},
+			want: TLS{Enabled: true, ClientAuth: "request", MinVersion: "default"},
+		},
+		{name: "any client certificate", cfg: &tls.Config{Certificates: cert, ClientAuth: tls.RequireAnyClientCert}, want: TLS{Enabled: true, ClientAuth: "require", MinVersion: "default"}},
+		{name: "verified if given", cfg: &tls.Config{Certificates: cert, ClientAuth: tls.VerifyClientCertIfGiven}, want: TLS{Enabled: true, ClientAuth: "verify", MinVersion: "default"}},
+		{name: "verified client certificate", cfg: &tls.Config{Certificates: cert, ClientAuth: tls.RequireAndVerifyClientCert}, want: TLS{Enabled: true, ClientAuth: "requireverify", MinVersion: "default"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TLSOf(tt.cfg); got != tt.want {
+				t.Errorf("TLSOf() = %+v, expected %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddress(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		bound   net.Addr
+		address string
+		port    int
+		want    string
+	}{
+		{name: "bound address", bound: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 41234}, address: "127.0.0.1", port: 0, want: "127.0.0.1:41234"},
+		{name: "configured IPv4", address: "0.0.0.0", port: 8080, want: "0.0.0.0:8080"},
+		{name: "configured IPv6", address: "::1", port: 8443, want: "[::1]:8443"},
+		{name: "configured IPv6 in brackets", address: "[::1]", port: 8443, want: "[::1]:8443"},
+		{name: "any address", port: 1883, want: ":1883"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Address(tt.bound, tt.address, tt.port); got != tt.want {
+				t.Errorf("Address() = %s, expected %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,317 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package listener
+
+import (
+	"errors"
+	"fmt"
+	"net/textproto"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LoadEnv - reads the environment variables starting with prefix and returns the listeners they describe initialized and ready to start, along with
+// the TLS config builder serving them, to be reloaded through `RunOptions.TLS` and closed once the listeners stopped, see `Build`.
+// Variables of the application sharing the prefix are ignored when their section is listed in appSections, see `ReadEnv`.
+// REST listeners still need a router, set it with `SetRouter` after finding the listener with `Listeners.Get`
+func LoadEnv(prefix string, appSections ...string) (ls Listeners, builders []*TLSConfigBuilder, err error) {
+
+	cfg, err := ReadEnv(prefix, appSections...)
+	if nil != err {
+		return nil, nil, err
+	}
+
+	return cfg.Build(nil)
+}
+
+// ReadEnv - reads the environment variables starting with prefix into a configuration, e.g. APP_REST_PORT for the prefix APP.
+// A listener is created for every protocol with at least one variable, the TLS variables apply to all of them that support TLS and share one builder.
+// Every invalid or unknown variable is reported, so a typo such as APP_RSET_PORT never goes unnoticed. Applications sharing the prefix for their
+// own variables list their sections in appSections, e.g. `ReadEnv("APP", "ENV", "DATABASE")` ignores APP_ENV and APP_DATABASE_URL
+func ReadEnv(prefix string, appSections ...string) (cfg *Config, err error) {
+	return readEnv(prefix, os.Environ(), appSections)
+}
+
+// readEnv - reads the configuration from the given environment, as returned by `os.Environ`, ignoring the sections of the application
+func readEnv(prefix string, environ []string, appSections []string) (cfg *Config, err error) {
+
+	prefix = strings.TrimSuffix(strings.ToUpper(strings.TrimSpace(prefix)), "_")
+	if len(prefix) == 0 {
+		return nil, errors.New("read env: prefix cannot be left blank")
+	}
+
+	prefix += "_"
+
+	ignored := make(map[string]bool, len(appSections))
+	for _, section := range appSections {
+		ignored[strings.ToUpper(strings.Trim(strings.TrimSpace(section), "_"))] = true
+	}
+
+	vars := make(map[string]string)
+	var keys []string
+
+	for _, kv := range environ {
+		k, v, _ := strings.Cut(kv, "=")
+		key, ok := strings.CutPrefix(k, prefix)
+		if !ok {
+			continue
+		}
+
+		vars[key] = v
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys) // report errors in a predictable order
+
+	var (
+		errs      []error
+		tlsConfig *TLSSettings
+		cors      *CORSSettings
+		listeners = make(map[Protocol]*ListenerConfig)
+	)
+
+	// listener - returns the configuration of the listener for proto, creating it on first use
+	listener := func(proto Protocol) (lc *ListenerConfig) {
+		if lc = listeners[proto]; nil == lc {
+			lc = &ListenerConfig{Protocol: proto.String()}
+			listeners[proto] = lc
+		}
+
+		return
+	}
+
+	for _, key := range keys {
+		value := strings.TrimSpace(vars[key])
+
+		var e error
+		section, field, _ := strings.Cut(key, "_")
+		if ignored[section] {
+			continue // owned by the application
+		}
+
+		switch section {
+		case "TLS":
+			if nil == tlsConfig {
+				tlsConfig = new(TLSSettings)
+			}
+			e = tlsConfig.setEnv(field, value)
+		case "CORS":
+			if nil == cors {
+				cors = new(CORSSettings)
+			}
+			e = cors.setEnv(field, value)
+		default:
+			proto := ParseProto(section)
+			if !proto.IsValid() {
+				e = fmt.Errorf("unknown section '%s', must be a protocol, TLS or CORS, or one of the sections of the application", section)
+				break
+			}
+			e = listener(proto).setEnv(field, value)
+		}
+
+		if nil != e {
+			errs = append(errs, fmt.Errorf("%s%s: %w", prefix, key, e))
+		}
+	}
+
+	if nil != cors {
+		lc := listener(ProtoREST) // CORS only applies to REST listeners
+		if nil == lc.REST {
+			lc.REST = new(RESTSettings)
+		}
+		lc.REST.CORS = cors
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("read env: %w", errors.Join(errs...))
+	}
+
+	if len(listeners) == 0 {
+		return nil, fmt.Errorf("read env: no listener variables set, e.g. %sREST_PORT", prefix)
+	}
+
+	cfg = new(Config)
+
+	// listeners are ordered by protocol so the configuration is the same for every run
+	protos := make([]Protocol, 0, len(listeners))
+	for proto := range listeners {
+		protos = append(protos, proto)
+	}
+	sort.Slice(protos, func(i, j int) bool { return protos[i] < protos[j] })
+
+	var secured bool
+
+	for _, proto := range protos {
+		lc := listeners[proto]
+
+		// every listener points to the same settings, so they share a single TLS config builder
+		if supportsTLS(proto) {
+			lc.TLS = tlsConfig
+			secured = true
+		}
+
+		cfg.Listeners = append(cfg.Listeners, *lc)
+	}
+
+	if nil != tlsConfig && !secured {
+		return nil, fmt.Errorf("read env: %sTLS variables set, but none of the listeners supports TLS", prefix)
+	}
+
+	return
+}
+
+// supportsTLS - determines if listeners of the protocol can be served over TLS
+func supportsTLS(proto Protocol) (supported bool) {
+	return proto != ProtoUDP // DTLS is not implemented
+}
+
+// setEnv - sets a listener field from its variable, REST listeners accept the REST settings as well
+func (lc *ListenerConfig) setEnv(field, value string) (err error) {
+
+	switch field {
+	case "NAME":
+		lc.Name = value
+		return
+	case "ADDRESS":
+		lc.Address = value
+		return
+	case "PORT":
+		lc.Port, err = envInt(value)
+		return
+	}
+
+	if ParseProto(lc.Protocol) != ProtoREST {
+		return errors.New("unknown variable")
+	}
+
+	if nil == lc.REST {
+		lc.REST = new(RESTSettings)
+	}
+
+	return lc.REST.setEnv(field, value)
+}
+
+// setEnv - sets a REST field from its variable
+func (s *RESTSettings) setEnv(field, value string) (err error) {
+
+	if name, ok := strings.CutPrefix(field, "HEADER_"); ok {
+		if nil == s.Headers {
+			s.Headers = make(map[string]string)
+		}
+
+		// underscores separate the words of the header name, e.g. HEADER_X_FRAME_OPTIONS sets X-Frame-Options
+		s.Headers[textproto.CanonicalMIMEHeaderKey(strings.ReplaceAll(name, "_", "-"))] = value
+		return
+	}
+
+	switch field {
+	case "RPS":
+		s.RPS, err = envInt(value)
+	case "TIMEOUT":
+		err = s.Timeout.UnmarshalText([]byte(value))
+	case "SHUTDOWN_TIMEOUT":
+		err = s.ShutdownTimeout.UnmarshalText([]byte(value))
+	case "COMPRESS":
+		s.Compress, err = envBool(value)
+	default:
+		err = errors.New("unknown variable")
+	}
+
+	return
+}
+
+// setEnv - sets a TLS field from its variable
+func (s *TLSSettings) setEnv(field, value string) (err error) {
+
+	switch field {
+	case "CERT":
+		s.Cert = value
+	case "KEY":
+		s.Key = value
+	case "CA":
+		s.CA = envList(value)
+	case "CA_DIR":
+		s.CADir = value
+	case "SYSTEM_CA":
+		s.SystemCA, err = envBool(value)
+	case "CLIENT_AUTH":
+		s.ClientAuth, err = ParseTLSClientAuth(value)
+	default:
+		err = errors.New("unknown variable")
+	}
+
+	return
+}
+
+// setEnv - sets a CORS field from its variable
+func (s *CORSSettings) setEnv(field, value string) (err error) {
+
+	switch field {
+	case "ORIGINS":
+		s.Origins = envList(value)
+	case "METHODS":
+		s.Methods = envList(value)
+	case "HEADERS":
+		s.Headers = envList(value)
+	case "MAX_AGE":
+		var maxAge int
+		maxAge, err = envInt(value)
+		s.MaxAge = &maxAge
+	case "ALLOW_CREDENTIALS":
+		var allow bool
+		allow, err = envBool(value)
+		s.AllowCredentials = &allow
+	case "DEBUG":
+		var debug bool
+		debug, err = envBool(value)
+		s.Debug = &debug
+	default:
+		err = errors.New("unknown variable")
+	}
+
+	return
+}
+
+// envInt - parses an integer value
+func envInt(value string) (i int, err error) {
+	if i, err = strconv.Atoi(value); nil != err {
+		return 0, fmt.Errorf("invalid integer '%s'", value)
+	}
+
+	return
+}
+
+// envBool - parses a boolean value, such as true, false, 1 or 0
+func envBool(value string) (b bool, err error) {
+	if b, err = strconv.ParseBool(value); nil != err {
+		return false, fmt.Errorf("invalid boolean '%s'", value)
+	}
+
+	return
+}
+
+// envList - splits a comma separated list, dropping empty entries
+func envList(value string) (list []string) {
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); len(v) > 0 {
+			list = append(list, v)
+		}
+	}
+
+	return
+}
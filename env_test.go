@@ -0,0 +1,146 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package listener
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadEnv(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		environ []string
+		app     []string // sections of the application
+		wantErr string
+		wantTLS map[string]bool // protocol of every listener and whether it is served over TLS
+	}{
+		{
+			name:    "variables of other prefixes are ignored",
+			environ: []string{"APP_REST_PORT=8080", "OTHER_REST_PORT=1", "APPLICATION_REST_PORT=1"},
+			wantTLS: map[string]bool{"REST": false},
+		},
+		{
+			name:    "sections of the application are ignored",
+			environ: []string{"APP_ENV=production", "APP_DATABASE_URL=postgres://db", "APP_REST_PORT=8080"},
+			app:     []string{"env", "DATABASE_"},
+			wantTLS: map[string]bool{"REST": false},
+		},
+		{
+			name:    "unlisted application variable",
+			environ: []string{"APP_ENV=production", "APP_REST_PORT=8080"},
+			wantErr: "APP_ENV: unknown section 'ENV'",
+		},
+		{
+			name:    "typo in a protocol",
+			environ: []string{"APP_RSET_PORT=8080", "APP_REST_PORT=8081"},
+			app:     []string{"ENV"},
+			wantErr: "APP_RSET_PORT: unknown section 'RSET'",
+		},
+		{
+			name:    "typo in the TLS section",
+			environ: []string{"APP_REST_PORT=8080", "APP_TSL_CERT=/app.crt"},
+			wantErr: "APP_TSL_CERT: unknown section 'TSL'",
+		},
+		{
+			name:    "typo in a listener variable",
+			environ: []string{"APP_REST_PROT=8080"},
+			wantErr: "APP_REST_PROT: unknown variable",
+		},
+		{
+			name:    "typo in a TLS variable",
+			environ: []string{"APP_REST_PORT=8080", "APP_TLS_CRT=/app.crt"},
+			wantErr: "APP_TLS_CRT: unknown variable",
+		},
+		{
+			name:    "TLS only for listeners supporting it",
+			environ: []string{"APP_REST_PORT=8443", "APP_MQTT_PORT=8883", "APP_UDP_PORT=9000", "APP_TLS_CERT=/app.crt", "APP_TLS_KEY=/app.key"},
+			wantTLS: map[string]bool{"REST": true, "MQTT": true, "UDP": false},
+		},
+		{
+			name:    "TLS without any listener supporting it",
+			environ: []string{"APP_UDP_PORT=9000", "APP_TLS_CERT=/app.crt", "APP_TLS_KEY=/app.key"},
+			wantErr: "none of the listeners supports TLS",
+		},
+		{
+			name:    "no listener variables",
+			environ: []string{"APP_ENV=production"},
+			app:     []string{"ENV"},
+			wantErr: "no listener variables set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := readEnv("APP", tt.environ, tt.app)
+			if len(tt.wantErr) > 0 {
+				if nil == err || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("readEnv() = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+
+			if nil != err {
+				t.Fatalf("readEnv(): %v", err)
+			}
+
+			got := make(map[string]bool)
+			for _, lc := range cfg.Listeners {
+				got[lc.Protocol] = nil != lc.TLS
+			}
+
+			if len(got) != len(tt.wantTLS) {
+				t.Fatalf("listeners = %v, want %v", got, tt.wantTLS)
+			}
+
+			for proto, tls := range tt.wantTLS {
+				if secured, ok := got[proto]; !ok || secured != tls {
+					t.Errorf("%s listener: served over TLS = %t (present %t), want %t", proto, secured, ok, tls)
+				}
+			}
+		})
+	}
+}
+
+func TestReadEnvSharesTLSBuilder(t *testing.T) {
+
+	certFile, keyFile := writeCert(t, t.TempDir())
+
+	cfg, err := readEnv("APP", []string{"APP_REST_PORT=-1", "APP_TCP_PORT=-1", "APP_MQTT_PORT=-1", "APP_UDP_PORT=-1", "APP_TLS_CERT=" + certFile, "APP_TLS_KEY=" + keyFile}, nil)
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	ls, builders, err := cfg.Build(nil)
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		for _, b := range builders {
+			b.Close()
+		}
+	}()
+
+	if len(ls) != 4 {
+		t.Errorf("%d listeners built, want 4", len(ls))
+	}
+
+	if len(builders) != 1 {
+		t.Errorf("%d TLS config builders created, want a single one shared by every listener", len(builders))
+	}
+}
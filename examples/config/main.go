@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/handletec/listener"
+	"github.com/handletec/listener/rest"
+)
+
+func main() {
+
+	// read and validate the file, errors of every listener are reported together
+	cfg, err := listener.ReadConfig("listeners.yaml")
+	if nil != err {
+		log.Println(err)
+		os.Exit(1)
+	}
+
+	// `listener.LoadConfig` does both steps when listeners may log to their own logger
+	listeners, listenerTLS, err := cfg.Build(nil)
+	if nil != err {
+		log.Println(err)
+		os.Exit(1)
+	}
+
+	// stop watching the certificate files once the listeners stopped
+	defer func() {
+		for _, t := range listenerTLS {
+			t.Close()
+		}
+	}()
+
+	restHandler := rest.NewNewHandler()
+	restHandler.Set(rest.MethodGet, "/server/list", serverList)
+
+	restRouter := rest.NewRouter("/api")
+	restRouter.SetHandler(restHandler)
+
+	// handlers cannot be described in a file, attach them to the listeners by name
+	restListener, ok := listeners.Get("public-api").(*rest.Listener)
+	if !ok {
+		log.Println("listener public-api is not configured")
+		os.Exit(1)
+	}
+
+	err = restListener.SetRouter(restRouter)
+	if nil != err {
+		log.Println(err)
+		os.Exit(1)
+	}
+
+	runOpts := listener.NewRunOptions()
+	runOpts.TLS = listenerTLS // SIGHUP reloads the certificates of every listener in the file
+
+	err = listener.Run(context.Background(), listeners, runOpts)
+	if nil != err {
+		log.Println(err)
+		os.Exit(1)
+	}
+}
+
+func serverList(w http.ResponseWriter, r *http.Request) {
+	log.Println("servers list called")
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"log/slog"
+	"os"
+
+	"github.com/handletec/listener"
+	"github.com/handletec/listener/mqtt"
+)
+
+func main() {
+
+	mqttHandler := mqtt.NewHandler(loggingMiddleWare)
+	mqttHandler.Set("sensors/+/temperature", temperature, validateMiddleWare)
+	mqttHandler.Set("devices/#", devices)
+
+	mqttConfig := mqtt.NewConfig()
+	mqttConfig.MaxQoS = 1 // grant at most QoS 1 to subscriptions
+	mqttConfig.SetHandler(mqttHandler)
+
+	mqttListener := mqtt.New()
+
+	err := mqttListener.SetConfig(mqttConfig)
+	if nil != err {
+		log.Println(err)
+		os.Exit(1)
+	}
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	// pass `listenerTLS.ForServer()` instead of nil to accept MQTTS connections, the default port then becomes 8883
+	mqttListener.Init(logger, mqtt.DefaultAddr, mqtt.DefaultPort, nil)
+
+	var listeners listener.Listeners
+	listeners.Add(mqttListener)
+
+	err = listener.Run(context.Background(), listeners, listener.NewRunOptions())
+	if nil != err {
+		log.Println(err)
+		os.Exit(1)
+	}
+}
+
+func temperature(ctx context.Context, msg *mqtt.Message) (err error) {
+	log.Println("temperature", string(msg.Payload), "from", msg.ClientID, "on", msg.Topic)
+	return
+}
+
+func devices(ctx context.Context, msg *mqtt.Message) (err error) {
+	log.Println("device update on", msg.Topic)
+	return
+}
+
+// middlewares must have the following structure
+func loggingMiddleWare(next mqtt.HandlerFunc) mqtt.HandlerFunc {
+	return func(ctx context.Context, msg *mqtt.Message) (err error) {
+
+		log.Println("calling logging middleware for", msg.Topic)
+
+		return next(ctx, msg)
+	}
+}
+
+// returning an error stops the message from being delivered to subscribers
+func validateMiddleWare(next mqtt.HandlerFunc) mqtt.HandlerFunc {
+	return func(ctx context.Context, msg *mqtt.Message) (err error) {
+
+		if len(msg.Payload) == 0 {
+			return errors.New("empty temperature reading")
+		}
+
+		return next(ctx, msg)
+	}
+}
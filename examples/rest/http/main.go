@@ -6,6 +6,8 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/handletec/listener/rest"
@@ -47,7 +49,12 @@ func main() {
 	//_ = logger
 
 	restListener.Init(logger, rest.DefaultAddr, rest.DefaultPort, nil)
-	err = restListener.Start()
+
+	// stop the listener gracefully, draining in-flight requests, when the process is interrupted
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err = restListener.Start(ctx)
 	if nil != err {
 		log.Println(err)
 		os.Exit(1)
@@ -64,7 +64,16 @@ func main() {
 	//_ = logger
 
 	restListener.Init(logger, rest.DefaultAddr, rest.DefaultPort, listenerTLS.ForServer())
-	err = restListener.Start()
+
+	var listeners listener.Listeners
+	listeners.Add(restListener)
+
+	// Run handles SIGINT/SIGTERM by draining in-flight requests and SIGHUP by reloading the certificate and key
+	runOpts := listener.NewRunOptions()
+	runOpts.Logger = logger
+	runOpts.TLS = append(runOpts.TLS, listenerTLS)
+
+	err = listener.Run(context.Background(), listeners, runOpts)
 	if nil != err {
 		log.Println(err)
 		os.Exit(1)
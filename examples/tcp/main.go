@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"log"
+	"log/slog"
+	"os"
+
+	"github.com/handletec/listener"
+	"github.com/handletec/listener/tcp"
+)
+
+func main() {
+
+	// every frame is prefixed with its length as a 2 byte big endian integer
+	tcpCodec, err := tcp.NewLengthPrefixCodec(2, binary.BigEndian, 4096)
+	if nil != err {
+		log.Println(err)
+		os.Exit(1)
+	}
+
+	tcpConfig := tcp.NewConfig()
+	tcpConfig.SetCodec(tcpCodec)
+	tcpConfig.OnConnect = func(conn *tcp.Conn) (err error) {
+		log.Println("device connected from", conn.RemoteAddr())
+		return
+	}
+
+	err = tcpConfig.SetHandler(echo, loggingMiddleWare)
+	if nil != err {
+		log.Println(err)
+		os.Exit(1)
+	}
+
+	tcpListener := tcp.New()
+
+	err = tcpListener.SetConfig(tcpConfig)
+	if nil != err {
+		log.Println(err)
+		os.Exit(1)
+	}
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	// pass `listenerTLS.ForServer()` instead of nil to accept TLS connections
+	tcpListener.Init(logger, tcp.DefaultAddr, tcp.DefaultPort, nil)
+
+	var listeners listener.Listeners
+	listeners.Add(tcpListener)
+
+	err = listener.Run(context.Background(), listeners, listener.NewRunOptions())
+	if nil != err {
+		log.Println(err)
+		os.Exit(1)
+	}
+}
+
+// echo - writes every frame back to the device
+func echo(conn *tcp.Conn, frame []byte) (err error) {
+	return conn.Write(frame)
+}
+
+// middlewares must have the following structure
+func loggingMiddleWare(next tcp.HandlerFunc) tcp.HandlerFunc {
+	return func(conn *tcp.Conn, frame []byte) (err error) {
+
+		log.Println("received", len(frame), "bytes from", conn.RemoteAddr())
+
+		return next(conn, frame)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/handletec/listener"
+	"github.com/handletec/listener/udp"
+)
+
+func main() {
+
+	udpConfig := udp.NewConfig()
+	udpConfig.Workers = 4
+	udpConfig.RateLimit = 100 // packets per second from each source
+	udpConfig.RateBurst = 200
+
+	err := udpConfig.SetHandler(collect, validateMiddleWare)
+	if nil != err {
+		log.Println(err)
+		os.Exit(1)
+	}
+
+	udpListener := udp.New()
+
+	err = udpListener.SetConfig(udpConfig)
+	if nil != err {
+		log.Println(err)
+		os.Exit(1)
+	}
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	udpListener.Init(logger, udp.DefaultAddr, udp.DefaultPort, nil)
+
+	// periodically report dropped packets
+	go func() {
+		for range time.Tick(time.Minute) {
+			stats := udpListener.Stats()
+			logger.Info("udp stats", "received", stats.Received, "handled", stats.Handled, "dropped", stats.Dropped())
+		}
+	}()
+
+	var listeners listener.Listeners
+	listeners.Add(udpListener)
+
+	err = listener.Run(context.Background(), listeners, listener.NewRunOptions())
+	if nil != err {
+		log.Println(err)
+		os.Exit(1)
+	}
+}
+
+// collect - acknowledges every packet received
+func collect(ctx context.Context, pkt *udp.Packet) (err error) {
+	log.Println("received", len(pkt.Data), "bytes from", pkt.Source)
+
+	return pkt.Reply([]byte("ack"))
+}
+
+// middlewares must have the following structure
+func validateMiddleWare(next udp.HandlerFunc) udp.HandlerFunc {
+	return func(ctx context.Context, pkt *udp.Packet) (err error) {
+
+		if len(pkt.Data) == 0 {
+			return errors.New("empty packet")
+		}
+
+		return next(ctx, pkt)
+	}
+}
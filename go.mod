@@ -9,6 +9,7 @@ require (
 	github.com/go-chi/render v1.0.3
 	github.com/samber/slog-chi v1.15.0
 	github.com/samber/slog-formatter v1.2.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
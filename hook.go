@@ -15,8 +15,21 @@ limitations under the License.
 */
 package listener
 
-// AuthN - custom authentication hook to be implemented by the caller
-//type AuthN func(i ...any) (err error)
+import (
+	"github.com/handletec/listener/auth"
+)
 
-// AuthZ - custom authorization hook to be implemented by the caller
-//type AuthZ func(i ...any) (err error)
+// Principal - identity of an authenticated client, stored in the context of the request or connection
+type Principal = auth.Principal
+
+// AuthN - authenticates a request or connection, turning it into a principal
+type AuthN = auth.Authenticator
+
+// AuthZ - decides if a principal may perform an action on a resource
+type AuthZ = auth.Authorizer
+
+// AuthNFunc - allows ordinary functions to be used as an authenticator
+type AuthNFunc = auth.AuthenticatorFunc
+
+// AuthZFunc - allows ordinary functions to be used as an authorizer
+type AuthZFunc = auth.AuthorizerFunc
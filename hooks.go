@@ -0,0 +1,36 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package listener
+
+import (
+	"time"
+
+	"github.com/handletec/listener/lifecycle"
+)
+
+// Hook - function run at a point in the lifecycle, ctx expires once the hook timeout is reached
+type Hook = lifecycle.Hook
+
+// Hooks - functions run at each point of the lifecycle, in the order they were added
+type Hooks = lifecycle.Hooks
+
+// NewHooks - creates new instance of hooks, with the given maximum time each hook may run
+func NewHooks(timeout time.Duration) (hooks *Hooks) {
+	hooks = new(Hooks)
+	hooks.SetTimeout(timeout)
+
+	return
+}
@@ -0,0 +1,117 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package accept
+
+import (
+	"crypto/tls"
+	"errors"
+	"log/slog"
+	"net"
+	"time"
+
+	"github.com/handletec/listener/connlimit"
+	"github.com/handletec/listener/ipfilter"
+	"github.com/handletec/listener/proxyproto"
+)
+
+const (
+	// minBackoff - delay before accepting again after the first failure
+	minBackoff = 5 * time.Millisecond
+
+	// maxBackoff - longest delay between failed accepts
+	maxBackoff = time.Second
+)
+
+// Chain - listeners wrapping a socket, shared by every stream listener so connections are checked the same way whatever the protocol
+type Chain struct {
+	IPFilter      *ipfilter.Filter   // (optional) allow and deny lists of client addresses
+	ConnLimit     *connlimit.Config  // (optional) caps the open connections, in total and from each client IP address
+	ProxyProtocol *proxyproto.Config // (optional) reads the PROXY header sent by trusted load balancers
+	TLS           *tls.Config        // (optional) serves over TLS
+}
+
+// Validate - checks the listeners can be combined. Connections are counted as soon as they are accepted, before the PROXY header names their
+// client, so a limit per IP address would only count the load balancer
+func (c Chain) Validate() (err error) {
+
+	if nil != c.ConnLimit && c.ConnLimit.MaxConnsPerIP > 0 && nil != c.ProxyProtocol {
+		return errors.New("connection limit per IP address cannot be used with the PROXY protocol, connections are counted against the load balancer, set it to 0")
+	}
+
+	return
+}
+
+// Wrap - wraps ln so accepted connections go through the IP filter, the connection limits, the PROXY protocol and TLS, in that order.
+// Denied clients and connections over the limits never reach the PROXY header or the TLS handshake, and the PROXY header is sent ahead of the handshake
+func (c Chain) Wrap(ln net.Listener, logger *slog.Logger) (wrapped net.Listener) {
+
+	wrapped = ln
+
+	if nil != c.IPFilter {
+		wrapped = ipfilter.NewListener(wrapped, c.IPFilter, logger)
+	}
+
+	if nil != c.ConnLimit {
+		wrapped = connlimit.NewListener(wrapped, c.ConnLimit, logger)
+	}
+
+	if nil != c.ProxyProtocol {
+		wrapped = proxyproto.NewListener(wrapped, c.ProxyProtocol)
+	}
+
+	if nil != c.TLS {
+		wrapped = tls.NewListener(wrapped, c.TLS)
+	}
+
+	return
+}
+
+// Serve - accepts connections until ln is closed, passing each one to handle, which returns false to stop accepting.
+// Failures such as running out of file descriptors clear up once connections close, so they are waited out with a growing delay,
+// logged once until a connection is accepted again. Returns nil when closing reports the listener is being shut down,
+// otherwise the error of a listener closed underneath the loop
+func Serve(ln net.Listener, logger *slog.Logger, closing func() bool, handle func(conn net.Conn) (ok bool)) (err error) {
+
+	var backoff time.Duration
+
+	for {
+		conn, err := ln.Accept()
+		if nil != err {
+			if closing() {
+				return nil
+			}
+
+			if errors.Is(err, net.ErrClosed) {
+				return err // closed without a shutdown, nothing more can be accepted
+			}
+
+			if backoff == 0 {
+				backoff = minBackoff
+				logger.Warn("accept failed, backing off", "error", err)
+			} else if backoff *= 2; backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+			time.Sleep(backoff)
+			continue
+		}
+
+		backoff = 0
+
+		if !handle(conn) {
+			return nil
+		}
+	}
+}
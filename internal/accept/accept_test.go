@@ -0,0 +1,124 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package accept
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/handletec/listener/connlimit"
+	"github.com/handletec/listener/proxyproto"
+)
+
+// errEMFILE - accept failure of a process out of file descriptors
+var errEMFILE = &net.OpError{Op: "accept", Net: "tcp", Err: os.NewSyscallError("accept", syscall.EMFILE)}
+
+// scriptedListener - returns the scripted results of Accept in order, a nil error accepts a connection
+type scriptedListener struct {
+	net.Listener
+	results []error
+}
+
+func (sl *scriptedListener) Accept() (conn net.Conn, err error) {
+	if len(sl.results) == 0 {
+		return nil, net.ErrClosed
+	}
+
+	err, sl.results = sl.results[0], sl.results[1:]
+	if nil != err {
+		return nil, err
+	}
+
+	conn, peer := net.Pipe()
+	peer.Close()
+
+	return conn, nil
+}
+
+func TestServe(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		results      []error
+		closing      bool
+		handleLimit  int // handle returns false once it was called this many times, 0 never stops
+		wantErr      error
+		wantHandled  int
+		wantWarnings int
+	}{
+		{name: "temporary errors are waited out", results: []error{errEMFILE, errEMFILE, nil, net.ErrClosed}, wantErr: net.ErrClosed, wantHandled: 1, wantWarnings: 1},
+		{name: "failures logged again once accepting recovered", results: []error{errEMFILE, nil, errEMFILE, nil}, wantErr: net.ErrClosed, wantHandled: 2, wantWarnings: 2},
+		{name: "closed by a shutdown", results: []error{nil, net.ErrClosed}, closing: true, wantHandled: 1},
+		{name: "closed without a shutdown", results: []error{net.ErrClosed}, wantErr: net.ErrClosed},
+		{name: "handle stops accepting", results: []error{nil, nil, nil}, handleLimit: 2, wantHandled: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&logs, nil))
+
+			var handled int
+			err := Serve(&scriptedListener{results: tt.results}, logger, func() bool { return tt.closing }, func(conn net.Conn) (ok bool) {
+				conn.Close()
+				handled++
+				return tt.handleLimit == 0 || handled < tt.handleLimit
+			})
+
+			if !errors.Is(err, tt.wantErr) || (nil == tt.wantErr && nil != err) {
+				t.Errorf("Serve() = %v, expected %v", err, tt.wantErr)
+			}
+
+			if handled != tt.wantHandled {
+				t.Errorf("handled %d connections, expected %d", handled, tt.wantHandled)
+			}
+
+			if warnings := strings.Count(logs.String(), "accept failed"); warnings != tt.wantWarnings {
+				t.Errorf("logged %d accept failures, expected %d\n%s", warnings, tt.wantWarnings, logs.String())
+			}
+		})
+	}
+}
+
+func TestChainValidate(t *testing.T) {
+
+	pp := new(proxyproto.Config)
+
+	tests := []struct {
+		name    string
+		chain   Chain
+		wantErr bool
+	}{
+		{name: "nothing to wrap", chain: Chain{}},
+		{name: "limits without the PROXY protocol", chain: Chain{ConnLimit: connlimit.NewConfig()}},
+		{name: "total limit with the PROXY protocol", chain: Chain{ConnLimit: &connlimit.Config{MaxConns: 100}, ProxyProtocol: pp}},
+		{name: "limit per IP address with the PROXY protocol", chain: Chain{ConnLimit: connlimit.NewConfig(), ProxyProtocol: pp}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.chain.Validate(); (nil != err) != tt.wantErr {
+				t.Errorf("Validate() = %v, expected error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,317 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package ipfilter
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// rules - allow and deny lists in use, replaced as a whole when reloaded
+type rules struct {
+	allow []netip.Prefix
+	deny  []netip.Prefix
+}
+
+// Filter - CIDR based allow and deny lists of client addresses. Deny rules take precedence, and once the allow list has an entry only the
+// addresses it matches are allowed. Safe to reload while connections are being checked
+type Filter struct {
+	rules   atomic.Pointer[rules]
+	path    string // file the rules were loaded from, empty if they were set in code
+	logger  *slog.Logger
+	watcher *fsnotify.Watcher
+	done    chan struct{}
+	mu      sync.Mutex // protects logger and watcher
+}
+
+// New - create new instance of a filter with the given CIDRs or IP addresses, e.g. `10.0.0.0/8` or `2001:db8::1`
+func New(allow, deny []string) (f *Filter, err error) {
+	f = new(Filter)
+	f.done = make(chan struct{})
+
+	if err = f.Set(allow, deny); nil != err {
+		return nil, err
+	}
+
+	return
+}
+
+// LoadFile - create new instance of a filter with rules read from a file, which is reloaded whenever it changes.
+// Each line holds `allow` or `deny` followed by a CIDR or IP address, blank lines and lines starting with `#` are ignored
+func LoadFile(path string) (f *Filter, err error) {
+	f = new(Filter)
+	f.done = make(chan struct{})
+	f.path = path
+
+	if err = f.Reload(); nil != err {
+		return nil, err
+	}
+
+	f.startWatcher()
+
+	return
+}
+
+// SetLogger - sets the logger reporting reloads of the file, defaults to `slog.Default()`
+func (f *Filter) SetLogger(logger *slog.Logger) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.logger = logger
+}
+
+// Set - replaces the allow and deny lists, the current lists are kept if any entry is invalid
+func (f *Filter) Set(allow, deny []string) (err error) {
+
+	r := new(rules)
+
+	if r.allow, err = parsePrefixes(allow); nil != err {
+		return fmt.Errorf("ip filter allow: %w", err)
+	}
+
+	if r.deny, err = parsePrefixes(deny); nil != err {
+		return fmt.Errorf("ip filter deny: %w", err)
+	}
+
+	f.rules.Store(r)
+
+	return
+}
+
+// Reload - reads the rules from the file again, useful on SIGHUP when the file is replaced without generating filesystem events.
+// The current rules are kept if the file cannot be read or has an invalid entry
+func (f *Filter) Reload() (err error) {
+
+	if len(f.path) == 0 {
+		return nil // rules were set in code, nothing to reload
+	}
+
+	data, err := os.ReadFile(f.path)
+	if nil != err {
+		return fmt.Errorf("load ip filter '%s': %w", f.path, err)
+	}
+
+	var allow, deny []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if len(text) == 0 || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return fmt.Errorf("load ip filter '%s': line %d: expected `allow` or `deny` followed by a CIDR", f.path, line)
+		}
+
+		if _, err = ParsePrefix(fields[1]); nil != err {
+			return fmt.Errorf("load ip filter '%s': line %d: %w", f.path, line, err)
+		}
+
+		switch strings.ToLower(fields[0]) {
+		case "allow":
+			allow = append(allow, fields[1])
+		case "deny":
+			deny = append(deny, fields[1])
+		default:
+			return fmt.Errorf("load ip filter '%s': line %d: unknown action '%s', must be allow or deny", f.path, line, fields[0])
+		}
+	}
+
+	if err = scanner.Err(); nil != err {
+		return fmt.Errorf("load ip filter '%s': %w", f.path, err)
+	}
+
+	return f.Set(allow, deny)
+}
+
+// Allow - determines if connections from addr are allowed, along with the reason for the decision.
+// Connections without an IP address such as Unix sockets are always allowed, as are all connections when no rules were set
+func (f *Filter) Allow(addr net.Addr) (allowed bool, reason string) {
+
+	var r *rules
+	if nil != f {
+		r = f.rules.Load()
+	}
+
+	if nil == r {
+		return true, "no rules" // filter declared without `New` or `LoadFile` and never set
+	}
+
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return true, "not an IP address"
+	}
+
+	ip, ok := netip.AddrFromSlice(tcpAddr.IP)
+	if !ok {
+		return false, "invalid IP address"
+	}
+
+	ip = ip.Unmap() // IPv4 clients of a dual stack socket are reported as IPv4 mapped IPv6 addresses
+
+	for _, prefix := range r.deny {
+		if prefix.Contains(ip) {
+			return false, "deny " + prefix.String()
+		}
+	}
+
+	for _, prefix := range r.allow {
+		if prefix.Contains(ip) {
+			return true, "allow " + prefix.String()
+		}
+	}
+
+	if len(r.allow) > 0 {
+		return false, "not in allow list"
+	}
+
+	return true, "no matching rule"
+}
+
+// Close - stops watching the file for changes
+func (f *Filter) Close() {
+	if nil == f {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if nil == f.watcher {
+		return
+	}
+
+	close(f.done)
+	f.watcher = nil
+}
+
+// log - returns the logger reporting reloads of the file
+func (f *Filter) log() (logger *slog.Logger) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if nil == f.logger {
+		return slog.Default()
+	}
+
+	return f.logger
+}
+
+// startWatcher - reloads the rules whenever the file is written or replaced
+func (f *Filter) startWatcher() {
+
+	w, err := fsnotify.NewWatcher()
+	if nil != err {
+		f.log().Warn("ip filter watcher failed, reload on SIGHUP instead", "path", f.path, "error", err)
+		return
+	}
+
+	// watch the directory, as editors and configuration management replace the file rather than writing to it
+	if err = w.Add(filepath.Dir(f.path)); nil != err {
+		w.Close()
+		f.log().Warn("ip filter watcher failed, reload on SIGHUP instead", "path", f.path, "error", err)
+		return
+	}
+
+	f.mu.Lock()
+	f.watcher = w
+	f.mu.Unlock()
+
+	path := filepath.Clean(f.path)
+
+	go func() {
+		defer w.Close()
+		for {
+			select {
+			case ev := <-w.Events:
+				if ev.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 || filepath.Clean(ev.Name) != path {
+					continue
+				}
+
+				time.Sleep(100 * time.Millisecond) // let the writer finish
+
+				if err := f.Reload(); nil != err {
+					f.log().Error("ip filter reload failed, keeping current rules", "path", f.path, "error", err)
+					continue
+				}
+
+				f.log().Info("ip filter reloaded", "path", f.path)
+			case err := <-w.Errors:
+				f.log().Warn("ip filter watcher error", "path", f.path, "error", err)
+			case <-f.done:
+				return
+			}
+		}
+	}()
+}
+
+// parsePrefixes - parses every CIDR or IP address
+func parsePrefixes(list []string) (prefixes []netip.Prefix, err error) {
+
+	for _, s := range list {
+		prefix, err := ParsePrefix(s)
+		if nil != err {
+			return nil, err
+		}
+
+		prefixes = append(prefixes, prefix)
+	}
+
+	return
+}
+
+// ParsePrefix - parses a CIDR, or a single IP address as a prefix matching only that address. IPv4 mapped IPv6 addresses and prefixes are
+// returned as plain IPv4, the way addresses of clients connecting to a dual stack socket are matched
+func ParsePrefix(cidr string) (prefix netip.Prefix, err error) {
+
+	cidr = strings.TrimSpace(cidr)
+
+	if !strings.Contains(cidr, "/") {
+		addr, err := netip.ParseAddr(cidr)
+		if nil != err {
+			return prefix, err
+		}
+
+		addr = addr.Unmap()
+
+		return netip.PrefixFrom(addr, addr.BitLen()), nil
+	}
+
+	if prefix, err = netip.ParsePrefix(cidr); nil != err {
+		return prefix, err
+	}
+
+	// IPv4 mapped prefixes are matched as plain IPv4, just like the addresses of clients
+	if addr, bits := prefix.Addr(), prefix.Bits(); addr.Is4In6() && bits >= 96 {
+		prefix = netip.PrefixFrom(addr.Unmap(), bits-96)
+	}
+
+	return prefix.Masked(), nil
+}
@@ -0,0 +1,229 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package ipfilter
+
+import (
+	"bytes"
+	"log/slog"
+	"net"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParsePrefix(t *testing.T) {
+
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "10.0.0.0/8", want: "10.0.0.0/8"},
+		{in: "10.1.2.3/8", want: "10.0.0.0/8"},
+		{in: " 192.0.2.15 ", want: "192.0.2.15/32"},
+		{in: "2001:db8::1", want: "2001:db8::1/128"},
+		{in: "2001:db8::/32", want: "2001:db8::/32"},
+		{in: "::ffff:192.0.2.15", want: "192.0.2.15/32"},
+		{in: "::ffff:10.0.0.0/104", want: "10.0.0.0/8"},
+		{in: "10.0.0.0/33", wantErr: true},
+		{in: "not-an-ip", wantErr: true},
+		{in: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParsePrefix(tt.in)
+			if tt.wantErr {
+				if nil == err {
+					t.Fatalf("ParsePrefix(%q) = %s, want error", tt.in, got)
+				}
+				return
+			}
+
+			if nil != err {
+				t.Fatalf("ParsePrefix(%q): %v", tt.in, err)
+			}
+
+			if got != netip.MustParsePrefix(tt.want) {
+				t.Errorf("ParsePrefix(%q) = %s, want %s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllow(t *testing.T) {
+
+	tcp := func(ip string) net.Addr {
+		return &net.TCPAddr{IP: net.ParseIP(ip), Port: 4000}
+	}
+
+	tests := []struct {
+		name    string
+		allow   []string
+		deny    []string
+		addr    net.Addr
+		allowed bool
+		reason  string
+	}{
+		{name: "no rules", addr: tcp("192.0.2.1"), allowed: true, reason: "no matching rule"},
+		{name: "denied", deny: []string{"192.0.2.0/24"}, addr: tcp("192.0.2.1"), allowed: false, reason: "deny 192.0.2.0/24"},
+		{name: "deny takes precedence", allow: []string{"192.0.2.0/24"}, deny: []string{"192.0.2.1"}, addr: tcp("192.0.2.1"), allowed: false, reason: "deny 192.0.2.1/32"},
+		{name: "allowed", allow: []string{"10.0.0.0/8"}, addr: tcp("10.1.2.3"), allowed: true, reason: "allow 10.0.0.0/8"},
+		{name: "not in allow list", allow: []string{"10.0.0.0/8"}, addr: tcp("192.0.2.1"), allowed: false, reason: "not in allow list"},
+		{name: "IPv4 client of dual stack socket", allow: []string{"10.0.0.0/8"}, addr: tcp("::ffff:10.1.2.3"), allowed: true, reason: "allow 10.0.0.0/8"},
+		{name: "IPv6", deny: []string{"2001:db8::/32"}, addr: tcp("2001:db8::1"), allowed: false, reason: "deny 2001:db8::/32"},
+		{name: "unix socket", allow: []string{"10.0.0.0/8"}, addr: &net.UnixAddr{Name: "/run/app.sock", Net: "unix"}, allowed: true, reason: "not an IP address"},
+		{name: "invalid IP address", allow: []string{"10.0.0.0/8"}, addr: &net.TCPAddr{}, allowed: false, reason: "invalid IP address"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := New(tt.allow, tt.deny)
+			if nil != err {
+				t.Fatal(err)
+			}
+
+			allowed, reason := f.Allow(tt.addr)
+			if allowed != tt.allowed || reason != tt.reason {
+				t.Errorf("Allow(%s) = %t, %q, want %t, %q", tt.addr, allowed, reason, tt.allowed, tt.reason)
+			}
+		})
+	}
+}
+
+func TestZeroValueFilter(t *testing.T) {
+
+	var f Filter
+
+	if allowed, reason := f.Allow(&net.TCPAddr{IP: net.ParseIP("192.0.2.1")}); !allowed || reason != "no rules" {
+		t.Errorf("zero value Allow = %t, %q, want true, \"no rules\"", allowed, reason)
+	}
+
+	var nilFilter *Filter
+	if allowed, _ := nilFilter.Allow(&net.TCPAddr{IP: net.ParseIP("192.0.2.1")}); !allowed {
+		t.Error("nil filter denied the connection")
+	}
+
+	f.Close()
+	nilFilter.Close()
+
+	if err := f.Set(nil, []string{"192.0.2.1"}); nil != err {
+		t.Fatal(err)
+	}
+
+	if allowed, _ := f.Allow(&net.TCPAddr{IP: net.ParseIP("192.0.2.1")}); allowed {
+		t.Error("zero value filter ignored rules set afterwards")
+	}
+}
+
+func TestReload(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "valid", content: "# comment\n\nallow 10.0.0.0/8\ndeny  10.66.0.0/16\n"},
+		{name: "unknown action", content: "allow 10.0.0.0/8\npermit 192.0.2.1\n", wantErr: "line 2: unknown action 'permit'"},
+		{name: "invalid CIDR", content: "deny 192.0.2.300\n", wantErr: "line 1:"},
+		{name: "missing CIDR", content: "allow\n", wantErr: "line 1: expected `allow` or `deny`"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "ipfilter.conf")
+			if err := os.WriteFile(path, []byte("deny 192.0.2.1\n"), 0o600); nil != err {
+				t.Fatal(err)
+			}
+
+			f, err := LoadFile(path)
+			if nil != err {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			if err = os.WriteFile(path, []byte(tt.content), 0o600); nil != err {
+				t.Fatal(err)
+			}
+
+			err = f.Reload()
+			if len(tt.wantErr) > 0 {
+				if nil == err || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Reload() = %v, want error containing %q", err, tt.wantErr)
+				}
+
+				// the rules loaded before are kept
+				if allowed, _ := f.Allow(&net.TCPAddr{IP: net.ParseIP("192.0.2.1")}); allowed {
+					t.Error("rules were replaced by an invalid file")
+				}
+				return
+			}
+
+			if nil != err {
+				t.Fatalf("Reload(): %v", err)
+			}
+
+			if allowed, reason := f.Allow(&net.TCPAddr{IP: net.ParseIP("10.66.1.1")}); allowed {
+				t.Errorf("Allow(10.66.1.1) = true, %q after reload", reason)
+			}
+		})
+	}
+}
+
+func TestListenerReportsDenied(t *testing.T) {
+
+	f, err := New(nil, []string{"127.0.0.1"})
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	var logs bytes.Buffer
+	fl := NewListener(ln, f, slog.New(slog.NewTextHandler(&logs, nil))) // info level
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fl.Accept() // only returns once closed, every client is denied
+	}()
+
+	for i := 0; i < 3; i++ {
+		conn, err := net.Dial("tcp", ln.Addr().String())
+		if nil != err {
+			t.Fatal(err)
+		}
+
+		conn.Read(make([]byte, 1)) // wait until the listener closes the connection
+		conn.Close()
+	}
+
+	fl.Close()
+	<-done
+
+	if got := strings.Count(logs.String(), "connections denied"); got != 1 {
+		t.Errorf("denied connections summarized %d times, want once per interval:\n%s", got, logs.String())
+	}
+
+	if strings.Contains(logs.String(), "connection denied") {
+		t.Errorf("denied connection logged at info level:\n%s", logs.String())
+	}
+}
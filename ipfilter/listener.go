@@ -0,0 +1,91 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package ipfilter
+
+import (
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+)
+
+// deniedReportInterval - denied connections are summarized at info level at most this often, so a flood of blocked clients does not flood the logs
+const deniedReportInterval = time.Minute
+
+// Listener - wraps a listener to close connections rejected by the filter as soon as they are accepted, before any TLS handshake or protocol work
+type Listener struct {
+	net.Listener
+	filter *Filter
+	logger *slog.Logger
+
+	mu       sync.Mutex
+	denied   int       // connections denied since the last summary
+	reported time.Time // when denied connections were last summarized
+}
+
+// NewListener - create new instance of a listener filtering the connections accepted by ln, decisions are logged through logger
+func NewListener(ln net.Listener, filter *Filter, logger *slog.Logger) (fl *Listener) {
+	fl = new(Listener)
+	fl.Listener = ln
+	fl.filter = filter
+
+	if nil == logger {
+		logger = slog.Default()
+	}
+
+	fl.logger = logger
+
+	return
+}
+
+// Accept - waits for the next connection allowed by the filter
+func (fl *Listener) Accept() (conn net.Conn, err error) {
+
+	for {
+		conn, err = fl.Listener.Accept()
+		if nil != err {
+			return nil, err
+		}
+
+		allowed, reason := fl.filter.Allow(conn.RemoteAddr())
+		if allowed {
+			fl.logger.Debug("connection allowed", "remote", conn.RemoteAddr().String(), "reason", reason)
+			return conn, nil
+		}
+
+		fl.logger.Debug("connection denied", "remote", conn.RemoteAddr().String(), "reason", reason)
+		conn.Close()
+
+		fl.reportDenied()
+	}
+}
+
+// reportDenied - counts a denied connection, logging how many were denied at info level once the report interval elapsed since the last summary
+func (fl *Listener) reportDenied() {
+	fl.mu.Lock()
+	defer fl.mu.Unlock()
+
+	fl.denied++
+
+	now := time.Now()
+	if now.Sub(fl.reported) < deniedReportInterval {
+		return
+	}
+
+	fl.logger.Info("connections denied", "count", fl.denied, "interval", deniedReportInterval.String())
+	fl.denied = 0
+	fl.reported = now
+}
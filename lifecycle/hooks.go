@@ -0,0 +1,160 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultHookTimeout - maximum time a single hook may run when no timeout is set
+const DefaultHookTimeout = 30 * time.Second
+
+// Event - point in the lifecycle at which hooks are run
+type Event uint8
+
+const (
+	EventInit        Event = iota // after the listener was initialized successfully
+	EventBeforeStart              // before the listener binds and accepts connections
+	EventStarted                  // once the listener is bound and accepting connections
+	EventShutdown                 // when shutdown begins, before in-flight requests are drained
+	EventStopped                  // once in-flight requests are drained and the listener stopped
+	eventCount
+)
+
+// String - returns the string representation of the event
+func (e Event) String() (str string) {
+	switch e {
+	case EventInit:
+		return "init"
+	case EventBeforeStart:
+		return "before start"
+	case EventStarted:
+		return "started"
+	case EventShutdown:
+		return "shutdown"
+	case EventStopped:
+		return "stopped"
+	}
+
+	return "unknown"
+}
+
+// Hook - function run at a point in the lifecycle, ctx expires once the hook timeout is reached
+type Hook func(ctx context.Context) (err error)
+
+// Hooks - functions run at each point of the lifecycle, in the order they were added. The zero value has no hooks and is ready to use
+type Hooks struct {
+	timeout time.Duration
+	hooks   [eventCount][]Hook
+	mu      sync.RWMutex // protects timeout and hooks
+}
+
+// SetTimeout - sets the maximum time each hook may run, a negative value lets hooks run for as long as the context given to `Run` allows
+func (h *Hooks) SetTimeout(timeout time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.timeout = timeout
+}
+
+// Add - adds a hook to run at the given event
+func (h *Hooks) Add(event Event, hook Hook) {
+
+	if event >= eventCount || nil == hook {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.hooks[event] = append(h.hooks[event], hook)
+}
+
+// OnInit - adds a hook run after the listener was initialized, an error fails the initialization
+func (h *Hooks) OnInit(hook Hook) {
+	h.Add(EventInit, hook)
+}
+
+// OnBeforeStart - adds a hook run before the listener accepts connections, an error stops the listener from starting
+func (h *Hooks) OnBeforeStart(hook Hook) {
+	h.Add(EventBeforeStart, hook)
+}
+
+// OnStarted - adds a hook run once the listener is accepting connections, an error shuts the listener down again
+func (h *Hooks) OnStarted(hook Hook) {
+	h.Add(EventStarted, hook)
+}
+
+// OnShutdown - adds a hook run when shutdown begins, before in-flight requests are drained. An error does not stop the drain but is returned by the shutdown
+func (h *Hooks) OnShutdown(hook Hook) {
+	h.Add(EventShutdown, hook)
+}
+
+// OnStopped - adds a hook run once the listener stopped and in-flight requests are drained. An error is returned by the shutdown
+func (h *Hooks) OnStopped(hook Hook) {
+	h.Add(EventStopped, hook)
+}
+
+// Run - runs the hooks of the event one after another, stopping at the first hook that returns an error or does not complete within the timeout
+func (h *Hooks) Run(ctx context.Context, event Event) (err error) {
+
+	if event >= eventCount {
+		return fmt.Errorf("%s hook: invalid event", event)
+	}
+
+	h.mu.RLock()
+	hooks := h.hooks[event]
+	timeout := h.timeout
+	h.mu.RUnlock()
+
+	if timeout == 0 {
+		timeout = DefaultHookTimeout
+	}
+
+	for i, hook := range hooks {
+		if err = runHook(ctx, hook, timeout); nil != err {
+			return fmt.Errorf("%s hook %d: %w", event, i+1, err)
+		}
+	}
+
+	return
+}
+
+// runHook - runs a single hook, returning once the hook completes or its context expires, whichever comes first
+func runHook(ctx context.Context, hook Hook, timeout time.Duration) (err error) {
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	done := make(chan error, 1) // buffered so a hook ignoring its context does not block forever once abandoned
+	go func() {
+		done <- hook(ctx)
+	}()
+
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	return
+}
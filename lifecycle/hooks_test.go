@@ -0,0 +1,184 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHooksRun(t *testing.T) {
+
+	errHook := errors.New("registration failed")
+
+	// record - hook appending its name to the order hooks ran in
+	record := func(order *[]string, name string, err error) Hook {
+		return func(ctx context.Context) error {
+			*order = append(*order, name)
+			return err
+		}
+	}
+
+	// block - hook ignoring its context, running until released
+	block := func(release <-chan struct{}) Hook {
+		return func(ctx context.Context) error {
+			<-release
+			return nil
+		}
+	}
+
+	tests := []struct {
+		name      string
+		timeout   time.Duration
+		ctxTime   time.Duration // deadline of the context given to Run, 0 has none
+		hooks     func(order *[]string, release <-chan struct{}) []Hook
+		wantOrder string
+		wantErr   error
+		wantMsg   string
+	}{
+		{
+			name: "no hooks",
+			hooks: func(order *[]string, release <-chan struct{}) []Hook {
+				return nil
+			},
+		},
+		{
+			name: "run in the order added",
+			hooks: func(order *[]string, release <-chan struct{}) []Hook {
+				return []Hook{record(order, "a", nil), record(order, "b", nil), record(order, "c", nil)}
+			},
+			wantOrder: "a b c",
+		},
+		{
+			name: "stop at the first error",
+			hooks: func(order *[]string, release <-chan struct{}) []Hook {
+				return []Hook{record(order, "a", nil), record(order, "b", errHook), record(order, "c", nil)}
+			},
+			wantOrder: "a b",
+			wantErr:   errHook,
+			wantMsg:   "started hook 2",
+		},
+		{
+			name:    "hook ignoring its context is abandoned at the timeout",
+			timeout: 20 * time.Millisecond,
+			hooks: func(order *[]string, release <-chan struct{}) []Hook {
+				return []Hook{block(release), record(order, "after", nil)}
+			},
+			wantErr: context.DeadlineExceeded,
+			wantMsg: "started hook 1",
+		},
+		{
+			name:    "negative timeout runs until the context expires",
+			timeout: -1,
+			ctxTime: 20 * time.Millisecond,
+			hooks: func(order *[]string, release <-chan struct{}) []Hook {
+				return []Hook{block(release)}
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+		{
+			name:    "timeout applies to each hook",
+			timeout: 50 * time.Millisecond,
+			hooks: func(order *[]string, release <-chan struct{}) []Hook {
+				slow := func(ctx context.Context) error {
+					time.Sleep(30 * time.Millisecond)
+					return nil
+				}
+				return []Hook{slow, slow, record(order, "done", nil)}
+			},
+			wantOrder: "done",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var order []string
+			release := make(chan struct{})
+			defer close(release)
+
+			var hooks Hooks
+			hooks.SetTimeout(tt.timeout)
+			for _, hook := range tt.hooks(&order, release) {
+				hooks.OnStarted(hook)
+			}
+
+			ctx := context.Background()
+			if tt.ctxTime > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.ctxTime)
+				defer cancel()
+			}
+
+			err := hooks.Run(ctx, EventStarted)
+			if !errors.Is(err, tt.wantErr) || (nil == tt.wantErr && nil != err) {
+				t.Fatalf("Run() = %v, expected %v", err, tt.wantErr)
+			}
+
+			if nil != err && !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("Run() = %v, expected it to name %q", err, tt.wantMsg)
+			}
+
+			if got := strings.Join(order, " "); got != tt.wantOrder {
+				t.Errorf("ran %q, expected %q", got, tt.wantOrder)
+			}
+		})
+	}
+}
+
+func TestHooksEvents(t *testing.T) {
+
+	var hooks Hooks
+	var ran []Event
+
+	for _, add := range []struct {
+		event Event
+		add   func(Hook)
+	}{
+		{EventInit, hooks.OnInit},
+		{EventBeforeStart, hooks.OnBeforeStart},
+		{EventStarted, hooks.OnStarted},
+		{EventShutdown, hooks.OnShutdown},
+		{EventStopped, hooks.OnStopped},
+	} {
+		event := add.event
+		add.add(func(ctx context.Context) error {
+			ran = append(ran, event)
+			return nil
+		})
+	}
+
+	// invalid events and nil hooks are ignored
+	hooks.Add(eventCount, func(ctx context.Context) error { return errors.New("must never run") })
+	hooks.Add(EventInit, nil)
+
+	for event := EventInit; event < eventCount; event++ {
+		ran = nil
+		if err := hooks.Run(context.Background(), event); nil != err {
+			t.Fatalf("Run(%s) = %v", event, err)
+		}
+
+		if len(ran) != 1 || ran[0] != event {
+			t.Errorf("Run(%s) ran the hooks of %v", event, ran)
+		}
+	}
+
+	if err := hooks.Run(context.Background(), eventCount); nil == err {
+		t.Error("Run() of an invalid event succeeded")
+	}
+}
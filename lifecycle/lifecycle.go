@@ -0,0 +1,124 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Lifecycle - state, hooks and readiness of a listener. Listeners embed it to provide `Ready`, `Subscribe` and `Hooks`, and move through
+// the lifecycle with `SetState`, `Started` and `Stopped`. The zero value is in the `Created` state and ready to use
+type Lifecycle struct {
+	hooks   Hooks
+	tracker Tracker
+	ready   chan struct{} // closed once started, replaced when the listener is initialized again
+	mu      sync.Mutex    // protects ready
+}
+
+// Ready - returns a channel that is closed once the listener is accepting connections and its started hooks completed
+func (lc *Lifecycle) Ready() <-chan struct{} {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	if nil == lc.ready {
+		lc.ready = make(chan struct{})
+	}
+
+	return lc.ready
+}
+
+// Status - returns the current state, when it was entered and the last error. The name is the one given to the last transition,
+// listeners that can be renamed set their current name
+func (lc *Lifecycle) Status() (status Status) {
+	return lc.tracker.Status()
+}
+
+// Subscribe - returns a channel receiving the status after every state transition, and a function to stop receiving them
+func (lc *Lifecycle) Subscribe(buffer int) (ch <-chan Status, cancel func()) {
+	return lc.tracker.Subscribe(buffer)
+}
+
+// Hooks - returns the hooks run at each point of the lifecycle
+func (lc *Lifecycle) Hooks() (hooks *Hooks) {
+	return &lc.hooks
+}
+
+// SetState - moves the listener with the given name to the state, a nil err keeps the last error.
+// Entering `Initialized` opens `Ready` again, so a listener initialized after it stopped is only ready once started again
+func (lc *Lifecycle) SetState(name string, state State, err error) {
+
+	if state == Initialized {
+		lc.mu.Lock()
+		if nil == lc.ready || isClosed(lc.ready) {
+			lc.ready = make(chan struct{})
+		}
+		lc.mu.Unlock()
+	}
+
+	lc.tracker.Set(name, state, err)
+}
+
+// Started - moves to `Running` once the listener is accepting connections, then runs the started hooks and closes `Ready` once they completed,
+// so anything waiting for the listener only proceeds once it is e.g. registered with service discovery. When a hook fails, `Ready` stays open
+// and the error is returned, the listener must then shut down as the application could not finish setting up
+func (lc *Lifecycle) Started(ctx context.Context, name string) (err error) {
+
+	lc.SetState(name, Running, nil)
+
+	if err = lc.hooks.Run(ctx, EventStarted); nil != err {
+		return
+	}
+
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	if nil == lc.ready {
+		lc.ready = make(chan struct{})
+	}
+
+	if !isClosed(lc.ready) {
+		close(lc.ready) // already closed when restarted without being initialized again
+	}
+
+	return
+}
+
+// Stopped - runs the stopped hooks once the listener drained, even if draining failed or ctx expired, then moves to `Stopped`,
+// or to `Failed` when err or a hook failed. Returns err joined with the errors of the hooks
+func (lc *Lifecycle) Stopped(ctx context.Context, name string, err error) error {
+
+	err = errors.Join(err, lc.hooks.Run(context.WithoutCancel(ctx), EventStopped))
+
+	state := Stopped
+	if nil != err {
+		state = Failed
+	}
+	lc.SetState(name, state, err)
+
+	return err
+}
+
+// isClosed - determines if the channel is closed
+func isClosed(ch chan struct{}) (closed bool) {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,209 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// ready - determines if the channel returned by `Ready` is closed
+func ready(lc *Lifecycle) (closed bool) {
+	select {
+	case <-lc.Ready():
+		return true
+	default:
+		return false
+	}
+}
+
+func TestLifecycleReady(t *testing.T) {
+
+	errHook := errors.New("registration failed")
+
+	tests := []struct {
+		name      string
+		run       func(t *testing.T, lc *Lifecycle)
+		wantReady bool
+		wantState State
+	}{
+		{
+			name:      "created",
+			run:       func(t *testing.T, lc *Lifecycle) {},
+			wantState: Created,
+		},
+		{
+			name: "started",
+			run: func(t *testing.T, lc *Lifecycle) {
+				lc.SetState("api", Initialized, nil)
+				if err := lc.Started(context.Background(), "api"); nil != err {
+					t.Fatal(err)
+				}
+			},
+			wantReady: true,
+			wantState: Running,
+		},
+		{
+			name: "started hook failed",
+			run: func(t *testing.T, lc *Lifecycle) {
+				lc.Hooks().OnStarted(func(ctx context.Context) error { return errHook })
+				lc.SetState("api", Initialized, nil)
+				if err := lc.Started(context.Background(), "api"); !errors.Is(err, errHook) {
+					t.Fatalf("Started() = %v, expected %v", err, errHook)
+				}
+			},
+			wantState: Running,
+		},
+		{
+			name: "initialized again after stopping",
+			run: func(t *testing.T, lc *Lifecycle) {
+				lc.SetState("api", Initialized, nil)
+				lc.Started(context.Background(), "api")
+				lc.Stopped(context.Background(), "api", nil)
+				lc.SetState("api", Initialized, nil)
+			},
+			wantState: Initialized,
+		},
+		{
+			name: "started again after initializing again",
+			run: func(t *testing.T, lc *Lifecycle) {
+				for i := 0; i < 2; i++ {
+					lc.SetState("api", Initialized, nil)
+					lc.Started(context.Background(), "api")
+					lc.Stopped(context.Background(), "api", nil)
+				}
+				lc.SetState("api", Initialized, nil)
+				lc.Started(context.Background(), "api")
+			},
+			wantReady: true,
+			wantState: Running,
+		},
+		{
+			name: "restarted without initializing again",
+			run: func(t *testing.T, lc *Lifecycle) {
+				lc.SetState("api", Initialized, nil)
+				lc.Started(context.Background(), "api")
+				lc.Stopped(context.Background(), "api", nil)
+				lc.Started(context.Background(), "api") // must not close the channel twice
+			},
+			wantReady: true,
+			wantState: Running,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lc := new(Lifecycle)
+			tt.run(t, lc)
+
+			if got := ready(lc); got != tt.wantReady {
+				t.Errorf("ready = %t, expected %t", got, tt.wantReady)
+			}
+
+			if state := lc.Status().State; state != tt.wantState {
+				t.Errorf("state = %s, expected %s", state, tt.wantState)
+			}
+		})
+	}
+}
+
+func TestLifecycleReadyWaiter(t *testing.T) {
+
+	lc := new(Lifecycle)
+	lc.SetState("api", Initialized, nil)
+
+	// a channel obtained before starting is the one closed once started
+	waiting := lc.Ready()
+
+	lc.Started(context.Background(), "api")
+
+	select {
+	case <-waiting:
+	default:
+		t.Fatal("channel obtained before starting not closed once started")
+	}
+
+	lc.Stopped(context.Background(), "api", nil)
+	lc.SetState("api", Initialized, nil)
+
+	if lc.Ready() == waiting {
+		t.Fatal("initializing again kept the closed channel")
+	}
+}
+
+func TestLifecycleStopped(t *testing.T) {
+
+	errDrain := errors.New("drain timed out")
+	errHook := errors.New("deregistration failed")
+
+	tests := []struct {
+		name      string
+		drainErr  error
+		hookErr   error
+		cancelled bool // context given to Stopped already expired
+		wantState State
+		wantErrs  []error
+	}{
+		{name: "clean stop", wantState: Stopped},
+		{name: "drain failed", drainErr: errDrain, wantState: Failed, wantErrs: []error{errDrain}},
+		{name: "stopped hook failed", hookErr: errHook, wantState: Failed, wantErrs: []error{errHook}},
+		{name: "both failed", drainErr: errDrain, hookErr: errHook, wantState: Failed, wantErrs: []error{errDrain, errHook}},
+		{name: "hooks run with an expired context", cancelled: true, wantState: Stopped},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lc := new(Lifecycle)
+
+			var ran bool
+			lc.Hooks().OnStopped(func(ctx context.Context) error {
+				ran = true
+				return ctx.Err()
+			})
+			lc.Hooks().OnStopped(func(ctx context.Context) error {
+				return tt.hookErr
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			if tt.cancelled {
+				cancel()
+			}
+			defer cancel()
+
+			err := lc.Stopped(ctx, "api", tt.drainErr)
+
+			if !ran {
+				t.Error("stopped hooks did not run")
+			}
+
+			for _, want := range tt.wantErrs {
+				if !errors.Is(err, want) {
+					t.Errorf("Stopped() = %v, expected it to wrap %v", err, want)
+				}
+			}
+
+			if len(tt.wantErrs) == 0 && nil != err {
+				t.Errorf("Stopped() = %v, expected nil", err)
+			}
+
+			status := lc.Status()
+			if status.State != tt.wantState || status.Err != err {
+				t.Errorf("status = %s, %v, expected %s, %v", status.State, status.Err, tt.wantState, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package lifecycle
+
+import (
+	"sync"
+	"time"
+)
+
+// State - stage of the lifecycle a listener is in
+type State uint8
+
+const (
+	Created     State = iota // listener was created but not initialized
+	Initialized              // listener was initialized and is ready to be started
+	Starting                 // listener is starting but is not yet accepting connections
+	Running                  // listener is bound and accepting connections
+	Draining                 // listener stopped accepting connections and is waiting for in-flight requests to complete
+	Stopped                  // listener stopped cleanly
+	Failed                   // listener stopped because of an error
+)
+
+// String - returns the string representation of the state
+func (s State) String() (str string) {
+	switch s {
+	case Created:
+		return "CREATED"
+	case Initialized:
+		return "INITIALIZED"
+	case Starting:
+		return "STARTING"
+	case Running:
+		return "RUNNING"
+	case Draining:
+		return "DRAINING"
+	case Stopped:
+		return "STOPPED"
+	case Failed:
+		return "FAILED"
+	}
+
+	return "UNKNOWN"
+}
+
+// Status - state of a listener at a point in time
+type Status struct {
+	Name  string    // name of the listener
+	State State     // current state
+	Since time.Time // time the listener entered the current state, zero for listeners that were never initialized
+	Err   error     // last error reported by the listener, kept across later transitions until another error occurs
+}
+
+// Tracker - records the state of a listener and notifies subscribers of every transition. The zero value is in the `Created` state and ready to use
+type Tracker struct {
+	status Status
+	subs   map[chan Status]struct{}
+	mu     sync.Mutex // protects status and subs
+}
+
+// Set - moves to the given state, a nil err keeps the last error
+func (t *Tracker) Set(name string, state State, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.status.State == state && nil == err {
+		return // not a transition, nothing to report
+	}
+
+	t.status.Name = name
+	t.status.State = state
+	t.status.Since = time.Now()
+	if nil != err {
+		t.status.Err = err
+	}
+
+	for ch := range t.subs {
+		notify(ch, t.status)
+	}
+}
+
+// Status - returns the current status
+func (t *Tracker) Status() (status Status) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.status
+}
+
+// Subscribe - returns a channel receiving the status after every transition, and a function to stop receiving them which closes the channel.
+// When the subscriber falls more than buffer transitions behind, the oldest transitions are discarded so the latest is always delivered
+func (t *Tracker) Subscribe(buffer int) (ch <-chan Status, cancel func()) {
+
+	if buffer < 1 {
+		buffer = 1
+	}
+
+	c := make(chan Status, buffer)
+
+	t.mu.Lock()
+	if nil == t.subs {
+		t.subs = make(map[chan Status]struct{})
+	}
+	t.subs[c] = struct{}{}
+	t.mu.Unlock()
+
+	var once sync.Once
+	cancel = func() {
+		once.Do(func() {
+			t.mu.Lock()
+			delete(t.subs, c)
+			t.mu.Unlock()
+
+			close(c)
+		})
+	}
+
+	return c, cancel
+}
+
+// notify - delivers the status without blocking, discarding the oldest pending status if the subscriber is not keeping up
+func notify(ch chan Status, status Status) {
+	for {
+		select {
+		case ch <- status:
+			return
+		default:
+		}
+
+		select {
+		case <-ch:
+		default:
+		}
+	}
+}
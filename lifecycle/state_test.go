@@ -0,0 +1,232 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package lifecycle
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTrackerSet(t *testing.T) {
+
+	errBind := errors.New("bind failed")
+	errDrain := errors.New("drain failed")
+
+	type transition struct {
+		state State
+		err   error
+	}
+
+	tests := []struct {
+		name        string
+		transitions []transition
+		wantStates  []State // states reported to a subscriber
+		wantState   State
+		wantErr     error
+	}{
+		{
+			name:        "full lifecycle",
+			transitions: []transition{{Initialized, nil}, {Starting, nil}, {Running, nil}, {Draining, nil}, {Stopped, nil}},
+			wantStates:  []State{Initialized, Starting, Running, Draining, Stopped},
+			wantState:   Stopped,
+		},
+		{
+			name:        "same state is not a transition",
+			transitions: []transition{{Initialized, nil}, {Initialized, nil}, {Starting, nil}, {Starting, nil}},
+			wantStates:  []State{Initialized, Starting},
+			wantState:   Starting,
+		},
+		{
+			name:        "same state with an error is reported",
+			transitions: []transition{{Running, nil}, {Running, errDrain}},
+			wantStates:  []State{Running, Running},
+			wantState:   Running,
+			wantErr:     errDrain,
+		},
+		{
+			name:        "error kept across later transitions",
+			transitions: []transition{{Starting, nil}, {Failed, errBind}, {Initialized, nil}, {Starting, nil}},
+			wantStates:  []State{Starting, Failed, Initialized, Starting},
+			wantState:   Starting,
+			wantErr:     errBind,
+		},
+		{
+			name:        "latest error replaces the previous one",
+			transitions: []transition{{Failed, errBind}, {Initialized, nil}, {Failed, errDrain}},
+			wantStates:  []State{Failed, Initialized, Failed},
+			wantState:   Failed,
+			wantErr:     errDrain,
+		},
+		{
+			name:        "restarted after stopping",
+			transitions: []transition{{Running, nil}, {Stopped, nil}, {Initialized, nil}, {Running, nil}},
+			wantStates:  []State{Running, Stopped, Initialized, Running},
+			wantState:   Running,
+		},
+		{
+			name:        "order is left to the listener, any state may follow another",
+			transitions: []transition{{Initialized, nil}, {Created, nil}},
+			wantStates:  []State{Initialized, Created},
+			wantState:   Created,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tracker Tracker
+
+			ch, cancel := tracker.Subscribe(len(tt.transitions))
+
+			for _, tr := range tt.transitions {
+				tracker.Set("api", tr.state, tr.err)
+			}
+
+			cancel()
+
+			var got []State
+			for status := range ch {
+				if status.Name != "api" || status.Since.IsZero() {
+					t.Errorf("status %+v has no name or time", status)
+				}
+				got = append(got, status.State)
+			}
+
+			if len(got) != len(tt.wantStates) {
+				t.Fatalf("reported %v, expected %v", got, tt.wantStates)
+			}
+
+			for i := range got {
+				if got[i] != tt.wantStates[i] {
+					t.Fatalf("reported %v, expected %v", got, tt.wantStates)
+				}
+			}
+
+			status := tracker.Status()
+			if status.State != tt.wantState || status.Err != tt.wantErr {
+				t.Errorf("Status() = %s, %v, expected %s, %v", status.State, status.Err, tt.wantState, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTrackerZeroValue(t *testing.T) {
+
+	var tracker Tracker
+
+	status := tracker.Status()
+	if status.State != Created || !status.Since.IsZero() || nil != status.Err {
+		t.Fatalf("zero value status = %+v, expected created without a time or error", status)
+	}
+}
+
+func TestTrackerSubscribe(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		buffer int
+		states []State
+		want   []State // states still pending once every transition was made, oldest discarded first
+	}{
+		{name: "subscriber keeping up", buffer: 4, states: []State{Initialized, Starting, Running}, want: []State{Initialized, Starting, Running}},
+		{name: "slow subscriber gets the latest", buffer: 2, states: []State{Initialized, Starting, Running, Draining}, want: []State{Running, Draining}},
+		{name: "buffer below one holds the latest", buffer: 0, states: []State{Initialized, Starting, Running}, want: []State{Running}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tracker Tracker
+
+			ch, cancel := tracker.Subscribe(tt.buffer)
+
+			for _, state := range tt.states {
+				tracker.Set("api", state, nil)
+			}
+
+			cancel()
+			cancel() // cancelling twice must not panic
+
+			var got []State
+			for status := range ch {
+				got = append(got, status.State)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("received %v, expected %v", got, tt.want)
+			}
+
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("received %v, expected %v", got, tt.want)
+				}
+			}
+
+			// transitions after cancelling must not reach the closed channel
+			tracker.Set("api", Failed, nil)
+		})
+	}
+}
+
+func TestTrackerSubscribers(t *testing.T) {
+
+	var tracker Tracker
+
+	first, cancelFirst := tracker.Subscribe(4)
+	second, cancelSecond := tracker.Subscribe(4)
+	defer cancelSecond()
+
+	tracker.Set("api", Initialized, nil)
+	cancelFirst()
+	tracker.Set("api", Starting, nil)
+
+	if status := <-first; status.State != Initialized {
+		t.Fatalf("first subscriber received %s, expected %s", status.State, Initialized)
+	}
+
+	if _, open := <-first; open {
+		t.Fatal("channel of a cancelled subscriber still open")
+	}
+
+	for _, want := range []State{Initialized, Starting} {
+		if status := <-second; status.State != want {
+			t.Fatalf("second subscriber received %s, expected %s", status.State, want)
+		}
+	}
+}
+
+func TestStateString(t *testing.T) {
+
+	tests := []struct {
+		state State
+		want  string
+	}{
+		{Created, "CREATED"},
+		{Initialized, "INITIALIZED"},
+		{Starting, "STARTING"},
+		{Running, "RUNNING"},
+		{Draining, "DRAINING"},
+		{Stopped, "STOPPED"},
+		{Failed, "FAILED"},
+		{Failed + 1, "UNKNOWN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.state.String(); got != tt.want {
+				t.Errorf("String() = %s, expected %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -16,9 +16,13 @@ limitations under the License.
 package listener
 
 import (
+	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net"
+	"sync"
 )
 
 // Listener - generic interface that specific interfaces must implement
@@ -26,7 +30,13 @@ type Listener interface {
 	Name() string
 	Init(logger *slog.Logger, address string, port int, tlsConfig *tls.Config) error
 	SetConfig(config any) error
-	Start() error
+	Start(ctx context.Context) error                        // blocks until the listener stops, cancelling ctx gracefully shuts it down
+	Shutdown(ctx context.Context) error                     // stops accepting new connections and drains in-flight requests until ctx expires
+	Addr() net.Addr                                         // address the listener is bound to, nil until the listener is bound
+	Ready() <-chan struct{}                                 // closed once the listener is accepting connections and its started hooks completed
+	Status() Status                                         // current lifecycle state, when it was entered and the last error
+	Subscribe(buffer int) (ch <-chan Status, cancel func()) // status after every state transition until cancel is called
+	Hooks() *Hooks                                          // functions run at each point of the lifecycle
 }
 
 // Listeners - slice of listeners for specific protocols
@@ -38,14 +48,72 @@ func (ls *Listeners) Add(l Listener) (err error) {
 	return
 }
 
-// StartAll - start all configured listeners
-func (ls Listeners) StartAll() (err error) {
+// Get - returns the listener with the given name, nil if there is none
+func (ls Listeners) Get(name string) (l Listener) {
+	for _, l = range ls {
+		if l.Name() == name {
+			return l
+		}
+	}
+
+	return nil
+}
+
+// StartAll - start all configured listeners concurrently and block until all of them stop.
+// If any listener fails, the remaining listeners are shutdown gracefully and the first error is returned
+func (ls Listeners) StartAll(ctx context.Context) (err error) {
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make(chan error, len(ls)) // buffered so no listener blocks while reporting its error
+	var wg sync.WaitGroup
 
 	for _, l := range ls {
-		err = l.Start()
-		if nil != err {
-			return fmt.Errorf("listeners startall: %w", err)
-		}
+		wg.Add(1)
+		go func(l Listener) {
+			defer wg.Done()
+
+			if e := l.Start(ctx); nil != e {
+				errs <- fmt.Errorf("%s: %w", l.Name(), e)
+				cancel() // stop all other listeners
+			}
+		}(l)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	// the channel preserves ordering, so the first value received is the first failure
+	if e, ok := <-errs; ok {
+		return fmt.Errorf("listeners startall: %w", e)
+	}
+
+	return
+}
+
+// ShutdownAll - gracefully shuts down all configured listeners concurrently, so every listener stops accepting connections at once and
+// drains its in-flight requests within the same deadline, waiting until ctx expires. Errors of every listener are joined in the order of the listeners
+func (ls Listeners) ShutdownAll(ctx context.Context) (err error) {
+
+	errs := make([]error, len(ls)) // each listener reports at its own index, keeping errors in the order of the listeners
+	var wg sync.WaitGroup
+
+	for i, l := range ls {
+		wg.Add(1)
+		go func(i int, l Listener) {
+			defer wg.Done()
+
+			if e := l.Shutdown(ctx); nil != e {
+				errs[i] = fmt.Errorf("%s: %w", l.Name(), e)
+			}
+		}(i, l)
+	}
+
+	wg.Wait()
+
+	if err = errors.Join(errs...); nil != err {
+		return fmt.Errorf("listeners shutdownall: %w", err)
 	}
 
 	return
@@ -0,0 +1,106 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package listener
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeListener - listener whose Shutdown waits until every listener of the test is shutting down
+type fakeListener struct {
+	name     string
+	wg       *sync.WaitGroup // done once for every listener shutting down
+	err      error
+	shutdown bool
+	hooks    Hooks
+}
+
+func (f *fakeListener) Name() string { return f.name }
+func (f *fakeListener) Init(*slog.Logger, string, int, *tls.Config) error {
+	return nil
+}
+func (f *fakeListener) SetConfig(any) error             { return nil }
+func (f *fakeListener) Start(ctx context.Context) error { <-ctx.Done(); return nil }
+func (f *fakeListener) Addr() net.Addr                  { return nil }
+func (f *fakeListener) Ready() <-chan struct{}          { return nil }
+func (f *fakeListener) Status() Status                  { return Status{} }
+func (f *fakeListener) Hooks() *Hooks                   { return &f.hooks }
+func (f *fakeListener) Subscribe(int) (<-chan Status, func()) {
+	return nil, func() {}
+}
+
+func (f *fakeListener) Shutdown(ctx context.Context) error {
+	f.shutdown = true
+	f.wg.Done()
+
+	// a listener shut down after the previous one finished draining never sees the others waiting
+	done := make(chan struct{})
+	go func() {
+		f.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return f.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestShutdownAllConcurrent(t *testing.T) {
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+
+	ls := Listeners{
+		&fakeListener{name: "a", wg: &wg},
+		&fakeListener{name: "b", wg: &wg, err: errors.New("drain failed")},
+		&fakeListener{name: "c", wg: &wg, err: errors.New("drain failed")},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := ls.ShutdownAll(ctx)
+	if nil == err {
+		t.Fatal("expected the errors of listeners b and c")
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("listeners were shut down one after another: %v", err)
+	}
+
+	// errors are joined in the order of the listeners
+	msg := err.Error()
+	if b, c := strings.Index(msg, "b: drain failed"), strings.Index(msg, "c: drain failed"); b < 0 || c < 0 || b > c {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, l := range ls {
+		if !l.(*fakeListener).shutdown {
+			t.Fatalf("%s was not shut down", l.Name())
+		}
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package listenertest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Response - response read in full, with assertions that can be chained. Failed assertions are reported without stopping the test
+type Response struct {
+	*http.Response
+	Body []byte
+	t    testing.TB
+	desc string // method and path of the request, prefixed to every failure
+}
+
+// Status - asserts the status code of the response
+func (r *Response) Status(code int) (resp *Response) {
+	r.t.Helper()
+
+	if r.StatusCode != code {
+		r.t.Errorf("%s: status %d, expected %d, body: %s", r.desc, r.StatusCode, code, r.Body)
+	}
+
+	return r
+}
+
+// Header - asserts the value of a response header
+func (r *Response) Header(key, value string) (resp *Response) {
+	r.t.Helper()
+
+	if got := r.Response.Header.Get(key); got != value {
+		r.t.Errorf("%s: header %s is '%s', expected '%s'", r.desc, key, got, value)
+	}
+
+	return r
+}
+
+// HasHeader - asserts the response has the header, with any value
+func (r *Response) HasHeader(key string) (resp *Response) {
+	r.t.Helper()
+
+	if len(r.Response.Header.Values(key)) == 0 {
+		r.t.Errorf("%s: header %s is missing", r.desc, key)
+	}
+
+	return r
+}
+
+// NoHeader - asserts the response does not have the header
+func (r *Response) NoHeader(key string) (resp *Response) {
+	r.t.Helper()
+
+	if values := r.Response.Header.Values(key); len(values) > 0 {
+		r.t.Errorf("%s: header %s is '%s', expected none", r.desc, key, strings.Join(values, ", "))
+	}
+
+	return r
+}
+
+// BodyContains - asserts the body contains the text
+func (r *Response) BodyContains(text string) (resp *Response) {
+	r.t.Helper()
+
+	if !bytes.Contains(r.Body, []byte(text)) {
+		r.t.Errorf("%s: body does not contain '%s', body: %s", r.desc, text, r.Body)
+	}
+
+	return r
+}
+
+// JSON - decodes the JSON body into v, failing the test if it is not valid JSON
+func (r *Response) JSON(v any) (resp *Response) {
+	r.t.Helper()
+
+	if err := json.Unmarshal(r.Body, v); nil != err {
+		r.t.Fatalf("%s: decode body: %v, body: %s", r.desc, err, r.Body)
+	}
+
+	return r
+}
+
+// JSONEq - asserts the body is the same JSON as expected regardless of formatting and key order.
+// Expected is either JSON text as a string or `[]byte`, or a value encoded as JSON before comparing
+func (r *Response) JSONEq(expected any) (resp *Response) {
+	r.t.Helper()
+
+	var want []byte
+
+	switch e := expected.(type) {
+	case string:
+		want = []byte(e)
+	case []byte:
+		want = e
+	default:
+		data, err := json.Marshal(e)
+		if nil != err {
+			r.t.Fatalf("%s: encode expected JSON: %v", r.desc, err)
+		}
+		want = data
+	}
+
+	var got, exp any
+
+	if err := json.Unmarshal(r.Body, &got); nil != err {
+		r.t.Errorf("%s: body is not valid JSON: %v, body: %s", r.desc, err, r.Body)
+		return r
+	}
+
+	if err := json.Unmarshal(want, &exp); nil != err {
+		r.t.Fatalf("%s: expected value is not valid JSON: %v", r.desc, err)
+	}
+
+	if !reflect.DeepEqual(got, exp) {
+		r.t.Errorf("%s: body is %s, expected %s", r.desc, bytes.TrimSpace(r.Body), want)
+	}
+
+	return r
+}
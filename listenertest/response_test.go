@@ -0,0 +1,128 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package listenertest
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+	"testing"
+)
+
+// recorder - records the failures reported by assertions instead of failing the test
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.Errorf(format, args...)
+	runtime.Goexit()
+}
+
+func TestResponse(t *testing.T) {
+
+	header := http.Header{"Content-Type": {"application/json"}, "X-Version": {"2"}}
+
+	tests := []struct {
+		name         string
+		body         string
+		assert       func(r *Response)
+		wantFailures int
+	}{
+		{
+			name: "chained assertions met",
+			body: `{"id": 1, "name": "alice"}`,
+			assert: func(r *Response) {
+				r.Status(http.StatusOK).Header("X-Version", "2").HasHeader("Content-Type").NoHeader("X-Debug").BodyContains(`"alice"`)
+			},
+		},
+		{
+			name: "every failed assertion reported",
+			body: `{"id": 1}`,
+			assert: func(r *Response) {
+				r.Status(http.StatusCreated).Header("X-Version", "3").HasHeader("X-Debug").NoHeader("X-Version").BodyContains("alice")
+			},
+			wantFailures: 5,
+		},
+		{
+			name:   "JSON regardless of formatting and key order",
+			body:   `{"name": "alice", "id": 1}`,
+			assert: func(r *Response) { r.JSONEq(`{"id":1,"name":"alice"}`).JSONEq([]byte(`{"id":1,"name":"alice"}`)) },
+		},
+		{
+			name:   "JSON of a value",
+			body:   `{"id": 1, "name": "alice"}`,
+			assert: func(r *Response) { r.JSONEq(map[string]any{"id": 1, "name": "alice"}) },
+		},
+		{
+			name:         "JSON differs",
+			body:         `{"id": 2, "name": "alice"}`,
+			assert:       func(r *Response) { r.JSONEq(`{"id": 1, "name": "alice"}`) },
+			wantFailures: 1,
+		},
+		{
+			name:         "body is not JSON",
+			body:         `alice`,
+			assert:       func(r *Response) { r.JSONEq(`{"name": "alice"}`) },
+			wantFailures: 1,
+		},
+		{
+			name: "decoded",
+			body: `{"id": 1}`,
+			assert: func(r *Response) {
+				var v struct{ ID int }
+				if r.JSON(&v); v.ID != 1 {
+					r.t.Errorf("decoded %d, expected 1", v.ID)
+				}
+			},
+		},
+		{
+			name: "decoding stops the test",
+			body: `alice`,
+			assert: func(r *Response) {
+				var v any
+				r.JSON(&v).Status(http.StatusCreated) // never reached
+			},
+			wantFailures: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := new(recorder)
+			r := &Response{Response: &http.Response{StatusCode: http.StatusOK, Header: header}, Body: []byte(tt.body), t: rec, desc: "GET /api/users/1"}
+
+			// run like a test, so an assertion stopping it only ends its goroutine
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				tt.assert(r)
+			}()
+			<-done
+
+			if len(rec.failures) != tt.wantFailures {
+				t.Errorf("%d failures reported, expected %d: %q", len(rec.failures), tt.wantFailures, rec.failures)
+			}
+		})
+	}
+}
@@ -0,0 +1,323 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package listenertest
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/handletec/listener"
+	"github.com/handletec/listener/memnet"
+	"github.com/handletec/listener/mqtt"
+	"github.com/handletec/listener/rest"
+	"github.com/handletec/listener/tcp"
+)
+
+const (
+	// DefaultAddr - listeners under test only accept connections from the same host
+	DefaultAddr = "127.0.0.1"
+
+	// MemoryAddr - address of listeners started in memory, so certificates issued for localhost verify without setting `ServerName`
+	MemoryAddr = "localhost"
+
+	// ephemeralPort - every listener lets the OS pick a free port when given -1
+	ephemeralPort = -1
+)
+
+// Options - options controlling how a listener is started for a test
+type Options struct {
+	Logger          *slog.Logger               // (optional) logger given to the listener, logs are discarded by default
+	Address         string                     // address to bind to, the port is always picked by the OS
+	TLS             *listener.TLSConfigBuilder // (optional) serves over TLS with this builder, which also provides the certificate and CA of the client
+	ClientTLS       *listener.TLSConfigBuilder // (optional) certificate and CA of the client when they differ from `TLS`
+	ServerName      string                     // (optional) name the server certificate is verified against, when it is not issued for the address
+	StartTimeout    time.Duration              // maximum time to wait for the listener to become ready
+	ShutdownTimeout time.Duration              // maximum time to wait for the listener to drain when the test ends
+	InMemory        bool                       // serve over in-memory connections instead of a socket, for REST, TCP and MQTT listeners
+}
+
+// NewOptions - creates new instance of options with sane default values
+func NewOptions() (opts *Options) {
+	opts = new(Options)
+	opts.Address = DefaultAddr
+	opts.StartTimeout = time.Duration(10 * time.Second)
+	opts.ShutdownTimeout = time.Duration(10 * time.Second)
+
+	return
+}
+
+// Server - listener started for a test, along with a client configured to reach it
+type Server struct {
+	listener.Listener
+	URL       string       // base URL of the listener, e.g. `https://127.0.0.1:34567`
+	Client    *http.Client // client trusting the certificate of the listener, presenting the client certificate when TLS is in use
+	t         testing.TB
+	clientTLS *tls.Config      // nil when the listener does not use TLS
+	mem       *memnet.Listener // nil when the listener is bound to a socket
+}
+
+// Start - initializes l on an ephemeral port, starts it and waits until it is ready. The listener is shut down when the test ends.
+// Any configuration such as the router or handler must be set on the listener before calling Start. Fails the test if the listener cannot start.
+// With `InMemory`, the `Listen` function of the configuration of the listener is replaced by an in-memory listener
+func Start(t testing.TB, l listener.Listener, opts *Options) (s *Server) {
+	t.Helper()
+
+	if nil == opts {
+		opts = NewOptions()
+	}
+
+	logger := opts.Logger
+	if nil == logger {
+		logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+
+	address := opts.Address
+	if len(address) == 0 {
+		address = DefaultAddr
+	}
+
+	var serverTLS *tls.Config
+	if nil != opts.TLS {
+		serverTLS = opts.TLS.ForServer()
+	}
+
+	if err := l.Init(logger, address, ephemeralPort, serverTLS); nil != err {
+		t.Fatalf("listenertest: init %s: %v", l.Name(), err)
+	}
+
+	var mem *memnet.Listener
+	if opts.InMemory {
+		mem = memnet.NewListener(MemoryAddr)
+
+		if err := useListen(l, mem.Listen); nil != err {
+			t.Fatalf("listenertest: in memory %s: %v", l.Name(), err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error, 1)
+	go func() {
+		done <- l.Start(ctx)
+	}()
+
+	// stop - shuts the listener down and waits for it to stop
+	stop := func() (err error) {
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), opts.ShutdownTimeout)
+		defer shutdownCancel()
+
+		err = l.Shutdown(shutdownCtx)
+		cancel()
+
+		return errors.Join(err, <-done)
+	}
+
+	select {
+	case <-l.Ready():
+	case err := <-done:
+		cancel()
+		t.Fatalf("listenertest: start %s: %v", l.Name(), err)
+	case <-time.After(opts.StartTimeout):
+		stop()
+		t.Fatalf("listenertest: start %s: not ready after %s", l.Name(), opts.StartTimeout)
+	}
+
+	s = &Server{Listener: l, t: t, mem: mem}
+
+	transport := &http.Transport{ForceAttemptHTTP2: true}
+	if nil != mem {
+		transport.DialContext = mem.DialContext
+	}
+	scheme := "http"
+
+	if nil != opts.TLS {
+		clientTLS := opts.ClientTLS
+		if nil == clientTLS {
+			clientTLS = opts.TLS
+		} else if err := clientTLS.Reload(); nil != err {
+			stop()
+			t.Fatalf("listenertest: client certificate: %v", err) // certificates set from files are only loaded when serving
+		}
+
+		s.clientTLS = clientTLS.ForClient()
+		s.clientTLS.ServerName = opts.ServerName
+
+		transport.TLSClientConfig = s.clientTLS
+		scheme = "https"
+	}
+
+	s.Client = &http.Client{Transport: transport}
+	s.URL = scheme + "://" + l.Addr().String()
+
+	t.Cleanup(func() {
+		transport.CloseIdleConnections()
+
+		if err := stop(); nil != err {
+			t.Errorf("listenertest: shutdown %s: %v", l.Name(), err)
+		}
+	})
+
+	return
+}
+
+// useListen - sets the listen function of the configuration of l, for the listeners that have one
+func useListen(l listener.Listener, listen func(network, address string) (net.Listener, error)) (err error) {
+
+	cl, ok := l.(interface{ Config() any })
+	if !ok {
+		return errors.New("listener has no configuration")
+	}
+
+	switch config := cl.Config().(type) {
+	case *rest.Config:
+		config.Listen = listen
+	case *tcp.Config:
+		config.Listen = listen
+	case *mqtt.Config:
+		config.Listen = listen
+	default:
+		return fmt.Errorf("configuration of type %T cannot listen in memory", config)
+	}
+
+	return
+}
+
+// Dial - opens a connection to the listener over TLS when it is in use, for listeners other than REST. The connection is closed when the test ends
+func (s *Server) Dial() (conn net.Conn) {
+	s.t.Helper()
+
+	var err error
+
+	if nil != s.mem {
+		conn, err = s.mem.Dial()
+	} else {
+		conn, err = net.Dial(s.Addr().Network(), s.Addr().String())
+	}
+
+	if nil == err && nil != s.clientTLS {
+		cfg := s.clientTLS.Clone()
+		if len(cfg.ServerName) == 0 {
+			cfg.ServerName = s.Addr().String()
+			if host, _, e := net.SplitHostPort(cfg.ServerName); nil == e {
+				cfg.ServerName = host
+			}
+		}
+
+		tlsConn := tls.Client(conn, cfg)
+		if err = tlsConn.Handshake(); nil != err {
+			conn.Close()
+		}
+		conn = tlsConn
+	}
+
+	if nil != err {
+		s.t.Fatalf("listenertest: dial %s: %v", s.Name(), err)
+	}
+
+	s.t.Cleanup(func() {
+		conn.Close()
+	})
+
+	return
+}
+
+// NewRequest - creates a request to the path of the listener. A body other than nil, `[]byte`, `string` or `io.Reader` is encoded as JSON,
+// and every body is sent with the `application/json` content type expected by REST listeners
+func (s *Server) NewRequest(method, path string, body any) (req *http.Request) {
+	s.t.Helper()
+
+	var r io.Reader
+
+	switch b := body.(type) {
+	case nil:
+	case []byte:
+		r = bytes.NewReader(b)
+	case string:
+		r = strings.NewReader(b)
+	case io.Reader:
+		r = b
+	default:
+		data, err := json.Marshal(b)
+		if nil != err {
+			s.t.Fatalf("listenertest: encode request body: %v", err)
+		}
+		r = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, s.URL+path, r)
+	if nil != err {
+		s.t.Fatalf("listenertest: new request: %v", err)
+	}
+
+	if nil != r {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return
+}
+
+// Do - sends the request with the client of the listener and reads the whole response
+func (s *Server) Do(req *http.Request) (resp *Response) {
+	s.t.Helper()
+
+	res, err := s.Client.Do(req)
+	if nil != err {
+		s.t.Fatalf("listenertest: %s %s: %v", req.Method, req.URL.Path, err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if nil != err {
+		s.t.Fatalf("listenertest: %s %s: read body: %v", req.Method, req.URL.Path, err)
+	}
+
+	return &Response{Response: res, Body: body, t: s.t, desc: fmt.Sprintf("%s %s", req.Method, req.URL.Path)}
+}
+
+// Get - sends a GET request to the path of the listener
+func (s *Server) Get(path string) (resp *Response) {
+	s.t.Helper()
+	return s.Do(s.NewRequest(http.MethodGet, path, nil))
+}
+
+// Post - sends a POST request with the body to the path of the listener, see `NewRequest` for how the body is encoded
+func (s *Server) Post(path string, body any) (resp *Response) {
+	s.t.Helper()
+	return s.Do(s.NewRequest(http.MethodPost, path, body))
+}
+
+// Put - sends a PUT request with the body to the path of the listener, see `NewRequest` for how the body is encoded
+func (s *Server) Put(path string, body any) (resp *Response) {
+	s.t.Helper()
+	return s.Do(s.NewRequest(http.MethodPut, path, body))
+}
+
+// Delete - sends a DELETE request to the path of the listener
+func (s *Server) Delete(path string) (resp *Response) {
+	s.t.Helper()
+	return s.Do(s.NewRequest(http.MethodDelete, path, nil))
+}
@@ -0,0 +1,174 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package listenertest
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/handletec/listener"
+	"github.com/handletec/listener/memnet"
+	"github.com/handletec/listener/rest"
+	"github.com/handletec/listener/tcp"
+	"github.com/handletec/listener/udp"
+)
+
+// newEcho - REST listener answering requests to `/api/echo` with their content type and body
+func newEcho(t *testing.T) (l *rest.Listener) {
+	t.Helper()
+
+	handler := rest.NewNewHandler()
+	echo := func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Write([]byte(r.Header.Get("Content-Type") + " " + string(body)))
+	}
+
+	for _, method := range []rest.Method{rest.MethodGet, rest.MethodPost, rest.MethodPut, rest.MethodDelete} {
+		if err := handler.Set(method, "/echo", echo); nil != err {
+			t.Fatal(err)
+		}
+	}
+
+	router := rest.NewRouter("/api")
+	router.SetHandler(handler)
+
+	cfg := rest.NewConfig()
+	cfg.SetRouter(router)
+
+	l = rest.New()
+	if err := l.SetConfig(cfg); nil != err {
+		t.Fatal(err)
+	}
+
+	return
+}
+
+func TestStart(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		inMemory bool
+		wantURL  string // prefix of the URL of the listener
+	}{
+		{name: "socket", wantURL: "http://127.0.0.1:"},
+		{name: "in memory", inMemory: true, wantURL: "http://" + MemoryAddr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := NewOptions()
+			opts.InMemory = tt.inMemory
+
+			s := Start(t, newEcho(t), opts)
+
+			if !strings.HasPrefix(s.URL, tt.wantURL) {
+				t.Errorf("URL = %s, expected it to start with %s", s.URL, tt.wantURL)
+			}
+
+			s.Get("/api/echo").Status(http.StatusOK)
+			s.Post("/api/echo", "hello").Status(http.StatusOK).BodyContains("hello")
+			s.Put("/api/echo", []byte("hello")).Status(http.StatusOK).BodyContains("hello")
+			s.Delete("/api/echo").Status(http.StatusOK)
+		})
+	}
+}
+
+func TestNewRequest(t *testing.T) {
+
+	s := Start(t, newEcho(t), nil)
+
+	tests := []struct {
+		name string
+		body any
+		want string // content type and body received by the listener
+	}{
+		{name: "no body", want: " "},
+		{name: "text", body: `{"id":1}`, want: `application/json {"id":1}`},
+		{name: "bytes", body: []byte(`{"id":1}`), want: `application/json {"id":1}`},
+		{name: "reader", body: bytes.NewBufferString(`{"id":1}`), want: `application/json {"id":1}`},
+		{name: "value encoded as JSON", body: struct {
+			ID int `json:"id"`
+		}{ID: 1}, want: `application/json {"id":1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := s.Do(s.NewRequest(http.MethodPost, "/api/echo", tt.body)).Status(http.StatusOK)
+
+			if got := string(resp.Body); got != tt.want {
+				t.Errorf("listener received %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDial(t *testing.T) {
+
+	cfg := tcp.NewConfig()
+	if err := cfg.SetHandler(func(conn *tcp.Conn, frame []byte) (err error) {
+		return conn.Write(bytes.ToUpper(frame))
+	}); nil != err {
+		t.Fatal(err)
+	}
+
+	l := tcp.New()
+	if err := l.SetConfig(cfg); nil != err {
+		t.Fatal(err)
+	}
+
+	opts := NewOptions()
+	opts.InMemory = true
+
+	conn := Start(t, l, opts).Dial()
+
+	if _, err := conn.Write([]byte("hello\n")); nil != err {
+		t.Fatal(err)
+	}
+
+	if line, err := bufio.NewReader(conn).ReadString('\n'); nil != err || line != "HELLO\n" {
+		t.Errorf("response = %q, %v, expected %q", line, err, "HELLO\n")
+	}
+}
+
+func TestUseListen(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		l       listener.Listener
+		config  any
+		wantErr bool
+	}{
+		{name: "REST", l: rest.New(), config: rest.NewConfig()},
+		{name: "TCP", l: tcp.New(), config: tcp.NewConfig()},
+		{name: "UDP cannot listen in memory", l: udp.New(), config: udp.NewConfig(), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.l.SetConfig(tt.config); nil != err {
+				t.Fatal(err)
+			}
+
+			if err := useListen(tt.l, memnet.NewListener("").Listen); (nil != err) != tt.wantErr {
+				t.Errorf("useListen() = %v, expected error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
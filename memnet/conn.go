@@ -0,0 +1,312 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package memnet
+
+import (
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// Addr - address of an in-memory listener or connection
+type Addr string
+
+// Network - returns the name of the network, always `memory`
+func (a Addr) Network() (network string) {
+	return "memory"
+}
+
+// String - returns the address
+func (a Addr) String() (str string) {
+	return string(a)
+}
+
+// buffer - bytes written by one end of a connection and not yet read by the other. Writes never block, unlike `net.Pipe`,
+// so both ends may write at the same time without deadlocking, as happens during TLS handshakes
+type buffer struct {
+	mu           sync.Mutex
+	data         []byte
+	closed       bool          // the writing end is closed, reads return io.EOF once the data is drained
+	readerClosed bool          // the reading end is closed, writes fail
+	signal       chan struct{} // closed and replaced whenever data is written or the buffer is closed
+}
+
+// newBuffer - create new instance of an empty buffer
+func newBuffer() (b *buffer) {
+	b = new(buffer)
+	b.signal = make(chan struct{})
+
+	return
+}
+
+// write - appends p to the data waiting to be read
+func (b *buffer) write(p []byte) (n int, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.readerClosed {
+		return 0, io.ErrClosedPipe
+	}
+
+	b.data = append(b.data, p...)
+	b.notify()
+
+	return len(p), nil
+}
+
+// read - reads the waiting data into p, waiting for data to be written until the connection is closed or the deadline passes
+func (b *buffer) read(p []byte, done <-chan struct{}, dl *deadline) (n int, err error) {
+
+	for {
+		select {
+		case <-done:
+			return 0, net.ErrClosed
+		case <-dl.wait():
+			return 0, os.ErrDeadlineExceeded
+		default:
+		}
+
+		b.mu.Lock()
+
+		if len(b.data) > 0 {
+			n = copy(p, b.data)
+			b.data = b.data[n:]
+			if len(b.data) == 0 {
+				b.data = nil // release the memory of large writes once they are read
+			}
+			b.mu.Unlock()
+
+			return
+		}
+
+		if b.closed {
+			b.mu.Unlock()
+			return 0, io.EOF
+		}
+
+		signal := b.signal
+		b.mu.Unlock()
+
+		select {
+		case <-signal:
+		case <-done:
+			return 0, net.ErrClosed
+		case <-dl.wait():
+			return 0, os.ErrDeadlineExceeded
+		}
+	}
+}
+
+// close - marks the writing end as closed
+func (b *buffer) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.closed {
+		b.closed = true
+		b.notify()
+	}
+}
+
+// closeReader - marks the reading end as closed, discarding the data waiting to be read
+func (b *buffer) closeReader() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.readerClosed = true
+	b.data = nil
+}
+
+// notify - wakes up the reader waiting for data, must be called with mu held
+func (b *buffer) notify() {
+	close(b.signal)
+	b.signal = make(chan struct{})
+}
+
+// deadline - read or write deadline of a connection, the channel returned by `wait` is closed once it passes
+type deadline struct {
+	mu     sync.Mutex
+	timer  *time.Timer
+	passed chan struct{}
+}
+
+// newDeadline - create new instance of a deadline that never passes
+func newDeadline() (d *deadline) {
+	d = new(deadline)
+	d.passed = make(chan struct{})
+
+	return
+}
+
+// set - sets the time the deadline passes, the zero value removes the deadline
+func (d *deadline) set(t time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if nil != d.timer && !d.timer.Stop() {
+		<-d.passed // the timer fired, wait for it to close the channel
+	}
+	d.timer = nil
+
+	passed := isClosed(d.passed)
+	if passed && (t.IsZero() || time.Until(t) > 0) {
+		d.passed = make(chan struct{}) // moved into the future, operations can wait again
+	}
+
+	if t.IsZero() {
+		return
+	}
+
+	if wait := time.Until(t); wait > 0 {
+		ch := d.passed
+		d.timer = time.AfterFunc(wait, func() {
+			close(ch)
+		})
+
+		return
+	}
+
+	if !passed {
+		close(d.passed)
+	}
+}
+
+// wait - returns a channel closed once the deadline passes
+func (d *deadline) wait() (ch <-chan struct{}) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.passed
+}
+
+// isClosed - determines if the channel is closed
+func isClosed(ch chan struct{}) (closed bool) {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+// conn - one end of an in-memory connection
+type conn struct {
+	in            *buffer // written by the other end
+	out           *buffer // read by the other end
+	local, remote net.Addr
+	done          chan struct{} // closed once this end is closed
+	once          sync.Once
+	readDeadline  *deadline
+	writeDeadline *deadline
+}
+
+// Pipe - creates both ends of an in-memory connection, data written to one end is read from the other. Unlike `net.Pipe`,
+// writes are buffered and never wait for the other end to read them
+func Pipe(local, remote net.Addr) (c1, c2 net.Conn) {
+
+	in, out := newBuffer(), newBuffer()
+
+	c1 = newConn(in, out, local, remote)
+	c2 = newConn(out, in, remote, local)
+
+	return
+}
+
+// newConn - create new instance of one end of a connection
+func newConn(in, out *buffer, local, remote net.Addr) (c *conn) {
+	c = new(conn)
+	c.in = in
+	c.out = out
+	c.local = local
+	c.remote = remote
+	c.done = make(chan struct{})
+	c.readDeadline = newDeadline()
+	c.writeDeadline = newDeadline()
+
+	return
+}
+
+// Read - reads data written by the other end, returning io.EOF once the other end is closed and all data was read
+func (c *conn) Read(p []byte) (n int, err error) {
+
+	if n, err = c.in.read(p, c.done, c.readDeadline); nil != err && err != io.EOF {
+		err = &net.OpError{Op: "read", Net: "memory", Source: c.local, Addr: c.remote, Err: err}
+	}
+
+	return
+}
+
+// Write - writes data to be read by the other end
+func (c *conn) Write(p []byte) (n int, err error) {
+
+	select {
+	case <-c.done:
+		err = net.ErrClosed
+	case <-c.writeDeadline.wait():
+		err = os.ErrDeadlineExceeded
+	default:
+		n, err = c.out.write(p)
+	}
+
+	if nil != err {
+		err = &net.OpError{Op: "write", Net: "memory", Source: c.local, Addr: c.remote, Err: err}
+	}
+
+	return
+}
+
+// Close - closes this end, the other end reads io.EOF once it read the data already written
+func (c *conn) Close() (err error) {
+	c.once.Do(func() {
+		close(c.done)
+		c.in.closeReader()
+		c.out.close()
+	})
+
+	return
+}
+
+// LocalAddr - returns the address of this end
+func (c *conn) LocalAddr() (addr net.Addr) {
+	return c.local
+}
+
+// RemoteAddr - returns the address of the other end
+func (c *conn) RemoteAddr() (addr net.Addr) {
+	return c.remote
+}
+
+// SetDeadline - sets the read and write deadlines
+func (c *conn) SetDeadline(t time.Time) (err error) {
+	c.readDeadline.set(t)
+	c.writeDeadline.set(t)
+
+	return
+}
+
+// SetReadDeadline - sets the time reads fail with a timeout, the zero value waits forever
+func (c *conn) SetReadDeadline(t time.Time) (err error) {
+	c.readDeadline.set(t)
+	return
+}
+
+// SetWriteDeadline - sets the time writes fail with a timeout, the zero value waits forever
+func (c *conn) SetWriteDeadline(t time.Time) (err error) {
+	c.writeDeadline.set(t)
+	return
+}
@@ -0,0 +1,292 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package memnet
+
+import (
+	"errors"
+	"io"
+	"net"
+	"os"
+	"testing"
+	"time"
+)
+
+// readResult - outcome of a read
+type readResult struct {
+	data string
+	err  error
+}
+
+// readAsync - reads from conn in the background
+func readAsync(conn net.Conn) (result chan readResult) {
+	result = make(chan readResult, 1)
+
+	go func() {
+		b := make([]byte, 16)
+		n, err := conn.Read(b)
+		result <- readResult{string(b[:n]), err}
+	}()
+
+	return
+}
+
+func TestConnReadDeadline(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		deadline func(conn net.Conn)       // sets the deadlines before reading
+		during   func(conn, peer net.Conn) // run while the read waits
+		want     readResult                // err is matched with errors.Is
+	}{
+		{
+			name:     "deadline in the past",
+			deadline: func(conn net.Conn) { conn.SetReadDeadline(time.Now().Add(-time.Second)) },
+			want:     readResult{err: os.ErrDeadlineExceeded},
+		},
+		{
+			name:     "deadline in the future",
+			deadline: func(conn net.Conn) { conn.SetReadDeadline(time.Now().Add(20 * time.Millisecond)) },
+			want:     readResult{err: os.ErrDeadlineExceeded},
+		},
+		{
+			name:     "data before the deadline",
+			deadline: func(conn net.Conn) { conn.SetReadDeadline(time.Now().Add(time.Second)) },
+			during:   func(conn, peer net.Conn) { peer.Write([]byte("hello")) },
+			want:     readResult{data: "hello"},
+		},
+		{
+			name: "passed deadline reset",
+			deadline: func(conn net.Conn) {
+				conn.SetReadDeadline(time.Now().Add(-time.Second))
+				conn.SetReadDeadline(time.Time{})
+			},
+			during: func(conn, peer net.Conn) { peer.Write([]byte("hello")) },
+			want:   readResult{data: "hello"},
+		},
+		{
+			name: "passed deadline moved into the future",
+			deadline: func(conn net.Conn) {
+				conn.SetReadDeadline(time.Now().Add(-time.Second))
+				conn.SetReadDeadline(time.Now().Add(time.Second))
+			},
+			during: func(conn, peer net.Conn) { peer.Write([]byte("hello")) },
+			want:   readResult{data: "hello"},
+		},
+		{
+			name: "deadline set while waiting",
+			during: func(conn, peer net.Conn) {
+				conn.SetDeadline(time.Now().Add(-time.Second))
+			},
+			want: readResult{err: os.ErrDeadlineExceeded},
+		},
+		{
+			name:     "write deadline leaves reads alone",
+			deadline: func(conn net.Conn) { conn.SetWriteDeadline(time.Now().Add(-time.Second)) },
+			during:   func(conn, peer net.Conn) { peer.Write([]byte("hello")) },
+			want:     readResult{data: "hello"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, peer := Pipe(Addr("a"), Addr("b"))
+			defer conn.Close()
+			defer peer.Close()
+
+			if nil != tt.deadline {
+				tt.deadline(conn)
+			}
+
+			result := readAsync(conn)
+
+			if nil != tt.during {
+				time.Sleep(10 * time.Millisecond) // let the read wait
+				tt.during(conn, peer)
+			}
+
+			select {
+			case got := <-result:
+				if got.data != tt.want.data || !errors.Is(got.err, tt.want.err) || (nil == tt.want.err && nil != got.err) {
+					t.Fatalf("Read() = %q, %v, expected %q, %v", got.data, got.err, tt.want.data, tt.want.err)
+				}
+
+				var ne net.Error
+				if nil != got.err && (!errors.As(got.err, &ne) || !ne.Timeout()) {
+					t.Errorf("Read() error %v is not a timeout", got.err)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatal("Read() still waiting")
+			}
+		})
+	}
+}
+
+func TestConnWriteDeadline(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		deadline func(conn net.Conn)
+		wantErr  error
+	}{
+		{name: "no deadline"},
+		{name: "deadline in the past", deadline: func(conn net.Conn) { conn.SetWriteDeadline(time.Now().Add(-time.Second)) }, wantErr: os.ErrDeadlineExceeded},
+		{name: "deadline in the future", deadline: func(conn net.Conn) { conn.SetWriteDeadline(time.Now().Add(time.Second)) }},
+		{
+			name: "passed deadline reset",
+			deadline: func(conn net.Conn) {
+				conn.SetDeadline(time.Now().Add(-time.Second))
+				conn.SetDeadline(time.Time{})
+			},
+		},
+		{name: "read deadline leaves writes alone", deadline: func(conn net.Conn) { conn.SetReadDeadline(time.Now().Add(-time.Second)) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, peer := Pipe(Addr("a"), Addr("b"))
+			defer conn.Close()
+			defer peer.Close()
+
+			if nil != tt.deadline {
+				tt.deadline(conn)
+			}
+
+			// writes never wait for the other end to read
+			n, err := conn.Write([]byte("hello"))
+			if !errors.Is(err, tt.wantErr) || (nil == tt.wantErr && nil != err) {
+				t.Fatalf("Write() = %d, %v, expected error %v", n, err, tt.wantErr)
+			}
+
+			if nil == tt.wantErr && n != 5 {
+				t.Errorf("Write() = %d, expected 5", n)
+			}
+		})
+	}
+}
+
+func TestConnClose(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		op      func(closed, peer net.Conn) (data string, err error) // run once closed was closed after writing "hello" to peer
+		want    string
+		wantErr error
+	}{
+		{
+			name: "other end reads the data written then EOF",
+			op: func(closed, peer net.Conn) (data string, err error) {
+				b, err := io.ReadAll(peer)
+				return string(b), err
+			},
+			want: "hello",
+		},
+		{
+			name: "other end reads EOF on every read",
+			op: func(closed, peer net.Conn) (data string, err error) {
+				io.ReadAll(peer)
+				_, err = peer.Read(make([]byte, 1))
+				return
+			},
+			wantErr: io.EOF,
+		},
+		{
+			name: "other end cannot write",
+			op: func(closed, peer net.Conn) (data string, err error) {
+				_, err = peer.Write([]byte("late"))
+				return
+			},
+			wantErr: io.ErrClosedPipe,
+		},
+		{
+			name: "closed end cannot read",
+			op: func(closed, peer net.Conn) (data string, err error) {
+				_, err = closed.Read(make([]byte, 1))
+				return
+			},
+			wantErr: net.ErrClosed,
+		},
+		{
+			name: "closed end cannot write",
+			op: func(closed, peer net.Conn) (data string, err error) {
+				_, err = closed.Write([]byte("late"))
+				return
+			},
+			wantErr: net.ErrClosed,
+		},
+		{
+			name: "closing again",
+			op: func(closed, peer net.Conn) (data string, err error) {
+				return "", closed.Close()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			closed, peer := Pipe(Addr("a"), Addr("b"))
+			defer peer.Close()
+
+			if _, err := closed.Write([]byte("hello")); nil != err {
+				t.Fatal(err)
+			}
+
+			if err := closed.Close(); nil != err {
+				t.Fatal(err)
+			}
+
+			peer.SetDeadline(time.Now().Add(time.Second))
+
+			got, err := tt.op(closed, peer)
+			if got != tt.want || !errors.Is(err, tt.wantErr) || (nil == tt.wantErr && nil != err) {
+				t.Errorf("got %q, %v, expected %q, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConnCloseWhileReading(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		close   func(conn, peer net.Conn)
+		wantErr error
+	}{
+		{name: "this end closed", close: func(conn, peer net.Conn) { conn.Close() }, wantErr: net.ErrClosed},
+		{name: "other end closed", close: func(conn, peer net.Conn) { peer.Close() }, wantErr: io.EOF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, peer := Pipe(Addr("a"), Addr("b"))
+			defer conn.Close()
+			defer peer.Close()
+
+			result := readAsync(conn)
+			time.Sleep(10 * time.Millisecond) // let the read wait
+
+			tt.close(conn, peer)
+
+			select {
+			case got := <-result:
+				if !errors.Is(got.err, tt.wantErr) {
+					t.Errorf("Read() = %q, %v, expected %v", got.data, got.err, tt.wantErr)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatal("Read() still waiting once closed")
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package memnet
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultName - address of listeners created without a name
+const DefaultName = "memory"
+
+// Listener - listener accepting in-memory connections opened with `Dial`, so listeners can be served and tested without opening sockets
+type Listener struct {
+	addr    Addr
+	conns   chan net.Conn // server end of connections waiting to be accepted
+	done    chan struct{} // closed once the listener is closed
+	closed  bool
+	clients atomic.Uint64 // number of connections dialed, used to give each client its own address
+	mu      sync.Mutex    // protects done and closed
+}
+
+// NewListener - create new instance of an in-memory listener, an empty name uses `DefaultName` as its address
+func NewListener(name string) (l *Listener) {
+
+	if len(name) == 0 {
+		name = DefaultName
+	}
+
+	l = new(Listener)
+	l.addr = Addr(name)
+	l.conns = make(chan net.Conn)
+	l.done = make(chan struct{})
+
+	return
+}
+
+// Listen - returns this listener whatever the network and address, reopening it if it was closed. Used as the `Listen` function
+// of listener configurations, so every time the listener is started it accepts connections dialed through this listener
+func (l *Listener) Listen(network, address string) (ln net.Listener, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		l.done = make(chan struct{})
+		l.closed = false
+	}
+
+	return l, nil
+}
+
+// Accept - waits for the next connection dialed through this listener
+func (l *Listener) Accept() (conn net.Conn, err error) {
+
+	select {
+	case conn = <-l.conns:
+		return conn, nil
+	case <-l.closing():
+		return nil, &net.OpError{Op: "accept", Net: "memory", Addr: l.addr, Err: net.ErrClosed}
+	}
+}
+
+// Close - stops accepting connections, connections already accepted stay open
+func (l *Listener) Close() (err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.closed {
+		close(l.done)
+		l.closed = true
+	}
+
+	return
+}
+
+// Addr - returns the address of this listener
+func (l *Listener) Addr() (addr net.Addr) {
+	return l.addr
+}
+
+// Dial - opens a connection to this listener, waiting for it to be accepted
+func (l *Listener) Dial() (conn net.Conn, err error) {
+	return l.DialContext(context.Background(), l.addr.Network(), l.addr.String())
+}
+
+// DialContext - opens a connection to this listener whatever the network and address, waiting for it to be accepted until ctx is done.
+// Matches the `DialContext` of `http.Transport` and `net.Dialer`, so clients reach this listener without any other change
+func (l *Listener) DialContext(ctx context.Context, network, address string) (conn net.Conn, err error) {
+
+	client := Addr(fmt.Sprintf("%s-client-%d", l.addr, l.clients.Add(1)))
+
+	conn, server := Pipe(client, l.addr)
+
+	select {
+	case l.conns <- server:
+		return conn, nil
+	case <-l.closing():
+		err = net.ErrClosed
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	conn.Close()
+	server.Close()
+
+	return nil, &net.OpError{Op: "dial", Net: "memory", Addr: l.addr, Err: err}
+}
+
+// closing - returns the channel closed once this listener is closed
+func (l *Listener) closing() (done <-chan struct{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.done
+}
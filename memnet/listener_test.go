@@ -0,0 +1,207 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package memnet
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// acceptAsync - accepts the next connection of ln in the background
+func acceptAsync(ln net.Listener) (result chan net.Conn, errs chan error) {
+	result = make(chan net.Conn, 1)
+	errs = make(chan error, 1)
+
+	go func() {
+		conn, err := ln.Accept()
+		if nil != err {
+			errs <- err
+			return
+		}
+		result <- conn
+	}()
+
+	return
+}
+
+func TestListenerDial(t *testing.T) {
+
+	l := NewListener("")
+	defer l.Close()
+
+	if got := l.Addr().String(); got != DefaultName {
+		t.Errorf("Addr() = %s, expected %s", got, DefaultName)
+	}
+
+	accepted, errs := acceptAsync(l)
+
+	client, err := l.Dial()
+	if nil != err {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	var server net.Conn
+	select {
+	case server = <-accepted:
+		defer server.Close()
+	case err = <-errs:
+		t.Fatal(err)
+	}
+
+	if server.LocalAddr() != l.Addr() || server.RemoteAddr() != client.LocalAddr() || client.RemoteAddr() != l.Addr() {
+		t.Errorf("addresses: server %s <- %s, client %s -> %s", server.LocalAddr(), server.RemoteAddr(), client.LocalAddr(), client.RemoteAddr())
+	}
+
+	second, err := acceptAndDial(t, l)
+	if nil != err {
+		t.Fatal(err)
+	}
+	defer second.Close()
+
+	if second.LocalAddr() == client.LocalAddr() {
+		t.Errorf("both clients have the address %s, expected their own", client.LocalAddr())
+	}
+
+	client.Write([]byte("ping"))
+	client.Close()
+
+	if b, err := io.ReadAll(server); nil != err || string(b) != "ping" {
+		t.Errorf("server read %q, %v, expected %q", b, err, "ping")
+	}
+}
+
+// acceptAndDial - dials l while accepting the connection in the background, returning the client end
+func acceptAndDial(t *testing.T, l *Listener) (client net.Conn, err error) {
+	t.Helper()
+
+	accepted, errs := acceptAsync(l)
+
+	if client, err = l.Dial(); nil != err {
+		return nil, err
+	}
+
+	select {
+	case server := <-accepted:
+		t.Cleanup(func() { server.Close() })
+	case err = <-errs:
+		client.Close()
+		return nil, err
+	}
+
+	return
+}
+
+func TestListenerClose(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		op      func(t *testing.T, l *Listener) (err error) // run once the listener is closed
+		wantErr error
+	}{
+		{
+			name: "accept",
+			op: func(t *testing.T, l *Listener) (err error) {
+				_, err = l.Accept()
+				return
+			},
+			wantErr: net.ErrClosed,
+		},
+		{
+			name: "dial",
+			op: func(t *testing.T, l *Listener) (err error) {
+				_, err = l.Dial()
+				return
+			},
+			wantErr: net.ErrClosed,
+		},
+		{
+			name: "close again",
+			op: func(t *testing.T, l *Listener) (err error) {
+				return l.Close()
+			},
+		},
+		{
+			name: "dial once reopened",
+			op: func(t *testing.T, l *Listener) (err error) {
+				if _, err = l.Listen("tcp", "127.0.0.1:0"); nil != err {
+					return
+				}
+
+				client, err := acceptAndDial(t, l)
+				if nil == err {
+					client.Close()
+				}
+
+				return
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewListener("api")
+			if err := l.Close(); nil != err {
+				t.Fatal(err)
+			}
+			defer l.Close()
+
+			if err := tt.op(t, l); !errors.Is(err, tt.wantErr) || (nil == tt.wantErr && nil != err) {
+				t.Errorf("got %v, expected %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestListenerCloseWhileAccepting(t *testing.T) {
+
+	l := NewListener("api")
+
+	accepted, errs := acceptAsync(l)
+	time.Sleep(10 * time.Millisecond) // let Accept wait
+
+	l.Close()
+
+	select {
+	case conn := <-accepted:
+		conn.Close()
+		t.Fatal("Accept() returned a connection once closed")
+	case err := <-errs:
+		if !errors.Is(err, net.ErrClosed) {
+			t.Errorf("Accept() = %v, expected %v", err, net.ErrClosed)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Accept() still waiting once closed")
+	}
+}
+
+func TestListenerDialContext(t *testing.T) {
+
+	l := NewListener("api")
+	defer l.Close()
+
+	// nothing accepts, so the dial waits until the context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := l.DialContext(ctx, "tcp", "api:80"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("DialContext() = %v, expected %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package mqtt
+
+import (
+	"crypto/tls"
+
+	"github.com/handletec/listener/auth"
+)
+
+const (
+	// ActionPublish - action authorized for every topic a client publishes to, including its will
+	ActionPublish = "publish"
+
+	// ActionSubscribe - action authorized for every topic filter a client subscribes to
+	ActionSubscribe = "subscribe"
+)
+
+// newAuthRequest - describes the CONNECT packet for authenticators, with the client identifier, username and password as metadata
+func (c *client) newAuthRequest(cp *connectPacket) (req *auth.Request) {
+	req = new(auth.Request)
+	req.Protocol = "MQTT"
+	req.RemoteAddr = c.conn.RemoteAddr()
+	req.Raw = c.conn
+
+	// the handshake already completed while the CONNECT packet was read
+	if tlsConn, ok := c.conn.(*tls.Conn); ok {
+		state := tlsConn.ConnectionState()
+		req.TLS = &state
+	}
+
+	req.Metadata = auth.Metadata{"Client-Id": {c.id}}
+
+	if len(cp.username) > 0 {
+		req.Metadata["Username"] = []string{cp.username}
+	}
+
+	if nil != cp.password {
+		req.Metadata["Password"] = []string{string(cp.password)}
+	}
+
+	return
+}
+
+// authenticate - authenticates the client with the details of its CONNECT packet and stores the principal in the context of the client,
+// returning the CONNACK code to reject the client with. Clients are accepted as they are when no authenticator is set
+func (c *client) authenticate(cp *connectPacket) (code byte, err error) {
+
+	if authn := c.l.config.authn; nil != authn {
+		if c.ctx, _, err = auth.Authenticate(c.ctx, authn, c.newAuthRequest(cp)); nil != err {
+			return connackBadCredentials, err
+		}
+	}
+
+	// the will is published on behalf of the client, so it must be allowed to publish to its topic
+	if nil != cp.will && !c.authorized(ActionPublish, cp.will.Topic) {
+		return connackNotAuthorized, auth.ErrForbidden
+	}
+
+	return connackAccepted, nil
+}
+
+// authorized - determines if the principal of the client may perform the action on the topic, always true when no authorizer is set
+func (c *client) authorized(action, topic string) (allowed bool) {
+
+	authz := c.l.config.authz
+	if nil == authz {
+		return true
+	}
+
+	if err := auth.Authorize(c.ctx, authz, action, topic); nil != err {
+		c.l.logger.Debug("client not authorized", "listener", c.l.Name(), "client", c.id, "action", action, "topic", topic, "error", err)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,110 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package mqtt_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/handletec/listener/auth"
+	"github.com/handletec/listener/listenertest"
+	"github.com/handletec/listener/mqtt"
+)
+
+func TestAuth(t *testing.T) {
+
+	authn := auth.AuthenticatorFunc(func(ctx context.Context, req *auth.Request) (p *auth.Principal, err error) {
+		if req.Metadata.Get("Username") != "alice" || req.Metadata.Get("Password") != "secret" || req.Metadata.Get("Client-Id") != "device-1" {
+			return nil, errors.New("wrong username or password")
+		}
+
+		if nil == req.RemoteAddr {
+			return nil, errors.New("no remote address")
+		}
+
+		return &auth.Principal{Subject: "alice"}, nil
+	})
+
+	authz := auth.AuthorizerFunc(func(ctx context.Context, p *auth.Principal, action, topic string) (err error) {
+		if !strings.HasPrefix(topic, "devices/"+p.Subject+"/") {
+			return auth.ErrForbidden
+		}
+		return
+	})
+
+	tests := []struct {
+		name        string
+		password    string
+		filter      string
+		wantConnack byte
+		wantSuback  byte
+	}{
+		{name: "authorized subscription", password: "secret", filter: "devices/alice/#", wantConnack: 0, wantSuback: 1},
+		{name: "forbidden subscription", password: "secret", filter: "devices/bob/#", wantConnack: 0, wantSuback: 0x80},
+		{name: "wrong password", password: "guess", wantConnack: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := mqtt.NewConfig()
+			cfg.SetAuth(authn, authz)
+
+			l := mqtt.New()
+			if err := l.SetConfig(cfg); nil != err {
+				t.Fatal(err)
+			}
+
+			opts := listenertest.NewOptions()
+			opts.InMemory = true
+
+			s := listenertest.Start(t, l, opts)
+			conn := s.Dial()
+			conn.SetDeadline(time.Now().Add(5 * time.Second))
+			r := bufio.NewReader(conn)
+
+			if code := connect(t, conn, r, "alice", tt.password); code != tt.wantConnack {
+				t.Fatalf("CONNACK code = %d, want %d", code, tt.wantConnack)
+			}
+
+			if tt.wantConnack != 0 {
+				if _, err := r.ReadByte(); !errors.Is(err, io.EOF) {
+					t.Errorf("connection still open after refused CONNECT: %v", err)
+				}
+				return
+			}
+
+			body := append([]byte{0, 1}, mqttString(tt.filter)...)
+			body = append(body, 1)
+			if _, err := conn.Write(packet(0x82, body)); nil != err {
+				t.Fatal(err)
+			}
+
+			suback := make([]byte, 5)
+			if _, err := io.ReadFull(r, suback); nil != err {
+				t.Fatalf("read SUBACK: %v", err)
+			}
+
+			if suback[4] != tt.wantSuback {
+				t.Errorf("SUBACK code = %#x, want %#x", suback[4], tt.wantSuback)
+			}
+		})
+	}
+}
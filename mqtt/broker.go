@@ -0,0 +1,304 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package mqtt
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// outbound - QoS 1 or 2 message sent to a client that has not been fully acknowledged
+type outbound struct {
+	msg      *Message
+	qos      byte
+	retain   bool
+	sent     bool // message was sent at least once, resending sets the DUP flag
+	released bool // PUBREC was received and PUBREL sent, waiting for PUBCOMP
+}
+
+// session - state of a client which outlives the connection when the client does not request a clean session
+type session struct {
+	id            string
+	clean         bool
+	client        *client         // connected client, nil while offline
+	subscriptions map[string]byte // topic filter to granted QoS
+	inflight      map[uint16]*outbound
+	order         []uint16            // packet identifiers of inflight messages in the order they were queued
+	received      map[uint16]struct{} // QoS 2 messages received from the client, waiting for PUBREL
+	nextID        uint16
+	mu            sync.Mutex // protects all fields above
+}
+
+// newSession - create new instance of session for the given client identifier
+func newSession(id string, clean bool) (s *session) {
+	s = new(session)
+	s.id = id
+	s.clean = clean
+	s.subscriptions = make(map[string]byte)
+	s.inflight = make(map[uint16]*outbound)
+	s.received = make(map[uint16]struct{})
+
+	return
+}
+
+// allocID - returns a packet identifier not in use by any inflight message, must be called with the lock held
+func (s *session) allocID() (id uint16) {
+	for {
+		s.nextID++
+		if s.nextID == 0 {
+			s.nextID = 1 // packet identifier 0 is not allowed
+		}
+		if _, used := s.inflight[s.nextID]; !used {
+			return s.nextID
+		}
+	}
+}
+
+// deliver - sends a message to the client, queueing QoS 1 and 2 messages until they are acknowledged. Returns false if the message was dropped
+func (s *session) deliver(msg *Message, qos byte, retain bool, maxQueued int) (delivered bool) {
+
+	s.mu.Lock()
+	c := s.client
+
+	if qos == 0 {
+		s.mu.Unlock()
+
+		if nil == c {
+			return false // QoS 0 messages are not kept for offline clients
+		}
+
+		return c.send(encodePublish(msg, 0, retain, 0, false), true)
+	}
+
+	if len(s.inflight) >= maxQueued {
+		s.mu.Unlock()
+		return false
+	}
+
+	id := s.allocID()
+	s.inflight[id] = &outbound{msg: msg, qos: qos, retain: retain, sent: nil != c}
+	s.order = append(s.order, id)
+	s.mu.Unlock()
+
+	if nil != c {
+		c.send(encodePublish(msg, qos, retain, id, false), false)
+	}
+
+	return true
+}
+
+// acknowledge - removes a message that has been fully acknowledged by the client
+func (s *session) acknowledge(id uint16) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.inflight[id]; !ok {
+		return
+	}
+
+	delete(s.inflight, id)
+	for i, oid := range s.order {
+		if oid == id {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// release - marks a QoS 2 message as received by the client, returning false if the message is unknown
+func (s *session) release(id uint16) (ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ob, ok := s.inflight[id]
+	if ok {
+		ob.released = true
+	}
+
+	return ok
+}
+
+// resume - returns all packets that must be resent to a client reconnecting to an existing session
+func (s *session) resume() (pkts [][]byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range s.order {
+		ob := s.inflight[id]
+		if ob.released {
+			pkts = append(pkts, encodeAck(packetPubrel, id))
+			continue
+		}
+
+		pkts = append(pkts, encodePublish(ob.msg, ob.qos, ob.retain, id, ob.sent))
+		ob.sent = true
+	}
+
+	return
+}
+
+// grantedQoS - returns the highest QoS of all subscriptions matching the topic, false if there is no matching subscription
+func (s *session) grantedQoS(topic string) (qos byte, matched bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for filter, q := range s.subscriptions {
+		if matchTopic(filter, topic) {
+			matched = true
+			if q > qos {
+				qos = q
+			}
+		}
+	}
+
+	return
+}
+
+// broker - sessions and retained messages shared by all clients of a listener
+type broker struct {
+	sessions map[string]*session
+	retained map[string]*Message // topic to retained message
+	mu       sync.RWMutex        // protects sessions and retained
+}
+
+// newBroker - create new instance of broker
+func newBroker() (b *broker) {
+	b = new(broker)
+	b.sessions = make(map[string]*session)
+	b.retained = make(map[string]*Message)
+
+	return
+}
+
+// attach - binds the client to its session, creating a new session when required. Any client already connected with the same identifier is returned so it can be disconnected
+func (b *broker) attach(c *client, id string, clean bool) (s *session, present bool, previous *client) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s, ok := b.sessions[id]
+	if ok {
+		s.mu.Lock()
+		previous = s.client
+		s.client = nil
+		s.mu.Unlock()
+	}
+
+	if !ok || clean || s.clean {
+		s = newSession(id, clean)
+		b.sessions[id] = s
+	} else {
+		present = true
+	}
+
+	s.mu.Lock()
+	s.client = c
+	s.mu.Unlock()
+
+	return
+}
+
+// detach - unbinds the client from its session, discarding clean sessions
+func (b *broker) detach(c *client) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s := c.session
+	if nil == s {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.client != c {
+		return // another connection has taken over this session
+	}
+
+	s.client = nil
+
+	if s.clean && b.sessions[s.id] == s {
+		delete(b.sessions, s.id)
+	}
+}
+
+// route - delivers a message to every session with a matching subscription, returning the number of sessions that dropped it
+func (b *broker) route(msg *Message, maxQueued int) (dropped int) {
+
+	b.mu.RLock()
+	sessions := make([]*session, 0, len(b.sessions))
+	for _, s := range b.sessions {
+		sessions = append(sessions, s)
+	}
+	b.mu.RUnlock()
+
+	for _, s := range sessions {
+		qos, matched := s.grantedQoS(msg.Topic)
+		if !matched {
+			continue
+		}
+
+		if msg.QoS < qos {
+			qos = msg.QoS // messages are delivered at the lower of the published and granted QoS
+		}
+
+		// live messages are never flagged as retained, only those sent when subscribing are
+		if !s.deliver(msg, qos, false, maxQueued) {
+			dropped++
+		}
+	}
+
+	return
+}
+
+// retain - stores the message as the retained message for its topic, an empty payload removes the retained message
+func (b *broker) retain(msg *Message) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(msg.Payload) == 0 {
+		delete(b.retained, msg.Topic)
+		return
+	}
+
+	b.retained[msg.Topic] = msg
+}
+
+// retainedFor - returns all retained messages matching the topic filter, sorted by topic
+func (b *broker) retainedFor(filter string) (msgs []*Message) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for topic, msg := range b.retained {
+		if matchTopic(filter, topic) {
+			msgs = append(msgs, msg)
+		}
+	}
+
+	sort.Slice(msgs, func(i, j int) bool {
+		return msgs[i].Topic < msgs[j].Topic
+	})
+
+	return
+}
+
+// clampQueued - limits the number of queued messages to the available packet identifiers
+func clampQueued(maxQueued int) int {
+	if maxQueued <= 0 || maxQueued > math.MaxUint16 {
+		return math.MaxUint16
+	}
+	return maxQueued
+}
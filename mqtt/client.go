@@ -0,0 +1,415 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// outboundBuffer - number of packets queued for each client before senders block or QoS 0 messages are dropped
+const outboundBuffer = 256
+
+// client - single network connection from an MQTT client
+type client struct {
+	l         *Listener
+	conn      net.Conn
+	r         *bufio.Reader
+	id        string
+	username  string
+	keepAlive time.Duration
+	will      *Message
+	session   *session
+	out       chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+	ctx       context.Context // cancelled once the client disconnects
+	cancel    context.CancelFunc
+}
+
+// newClient - create new instance of client for an accepted connection
+func newClient(l *Listener, conn net.Conn) (c *client) {
+	c = new(client)
+	c.l = l
+	c.conn = conn
+	c.r = bufio.NewReader(conn)
+	c.out = make(chan []byte, outboundBuffer)
+	c.done = make(chan struct{})
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+
+	return
+}
+
+// serve - processes packets from the client until it disconnects
+func (c *client) serve() {
+	defer c.close()
+
+	if err := c.connect(); nil != err {
+		c.l.logger.Debug("client connect rejected", "listener", c.l.Name(), "remote", c.conn.RemoteAddr().String(), "error", err)
+		c.l.broker.detach(c)
+		return
+	}
+
+	c.l.logger.Debug("client connected", "listener", c.l.Name(), "client", c.id, "remote", c.conn.RemoteAddr().String())
+
+	graceful := false
+
+	for {
+		if c.keepAlive > 0 {
+			// clients must send a packet within one and a half times the keep alive period
+			c.conn.SetReadDeadline(time.Now().Add(c.keepAlive + c.keepAlive/2))
+		} else {
+			c.conn.SetReadDeadline(time.Time{})
+		}
+
+		if c.l.isClosing() {
+			break // listener is shutting down, do not start processing another packet
+		}
+
+		hdr, body, err := readPacket(c.r, c.l.config.MaxPacketSize)
+		if nil != err {
+			if !errors.Is(err, io.EOF) && !c.l.isClosing() {
+				c.l.logger.Debug("client read failed", "listener", c.l.Name(), "client", c.id, "error", err)
+			}
+			break
+		}
+
+		if hdr.kind == packetDisconnect {
+			graceful = true
+			break
+		}
+
+		if err = c.handle(hdr, body); nil != err {
+			c.l.logger.Warn("client protocol error", "listener", c.l.Name(), "client", c.id, "packet", hdr.kind.String(), "error", err)
+			break
+		}
+	}
+
+	// the will is only published when the client disappears without saying goodbye
+	if !graceful && nil != c.will && !c.l.isClosing() {
+		c.l.publish(context.Background(), c.will)
+	}
+
+	c.l.broker.detach(c)
+	c.l.logger.Debug("client disconnected", "listener", c.l.Name(), "client", c.id, "graceful", graceful)
+}
+
+// connect - waits for the CONNECT packet and binds the client to its session
+func (c *client) connect() (err error) {
+
+	c.conn.SetReadDeadline(time.Now().Add(c.l.config.ConnectTimeout))
+
+	hdr, body, err := readPacket(c.r, c.l.config.MaxPacketSize)
+	if nil != err {
+		return err
+	}
+
+	if hdr.kind != packetConnect {
+		return fmt.Errorf("expected CONNECT, received %s", hdr.kind)
+	}
+
+	cp, code, err := decodeConnect(body)
+	if nil != err {
+		if code != connackAccepted {
+			c.writeNow(encodeConnack(false, code))
+		}
+		return err
+	}
+
+	if len(cp.clientID) == 0 {
+		if !cp.cleanSession {
+			c.writeNow(encodeConnack(false, connackIdentifierRejected))
+			return errors.New("empty client identifier requires a clean session")
+		}
+
+		// clients may leave the identifier blank and let the server assign one
+		buf := make([]byte, 8)
+		rand.Read(buf)
+		cp.clientID = "auto-" + hex.EncodeToString(buf)
+	}
+
+	c.id = cp.clientID
+	c.username = cp.username
+	c.keepAlive = time.Duration(cp.keepAlive) * time.Second
+
+	if code, err = c.authenticate(cp); nil != err {
+		c.writeNow(encodeConnack(false, code))
+		return err
+	}
+
+	if nil != cp.will {
+		cp.will.ClientID = c.id
+		cp.will.Username = c.username
+		c.will = cp.will
+	}
+
+	s, present, previous := c.l.broker.attach(c, c.id, cp.cleanSession)
+	c.session = s
+
+	if nil != previous {
+		c.l.logger.Debug("client taken over by new connection", "listener", c.l.Name(), "client", c.id)
+		previous.close()
+	}
+
+	// CONNACK must be the first packet the client receives, before anything queued on the session
+	if err = c.writeNow(encodeConnack(present, connackAccepted)); nil != err {
+		return err
+	}
+
+	go c.write()
+
+	for _, pkt := range s.resume() {
+		c.send(pkt, false)
+	}
+
+	return
+}
+
+// handle - processes a single packet after the client has connected
+func (c *client) handle(hdr fixedHeader, body []byte) (err error) {
+
+	switch hdr.kind {
+	case packetPubrel, packetSubscribe, packetUnsubscribe:
+		if hdr.flags != flagsPubrel {
+			return fmt.Errorf("%w: invalid fixed header flags", errMalformed)
+		}
+	case packetPublish:
+		// flags carry the QoS, retain and duplicate details
+	default:
+		if hdr.flags != 0 {
+			return fmt.Errorf("%w: invalid fixed header flags", errMalformed)
+		}
+	}
+
+	switch hdr.kind {
+	case packetPublish:
+		return c.handlePublish(hdr, body)
+
+	case packetPuback, packetPubcomp:
+		id, err := decodeAck(body)
+		if nil != err {
+			return err
+		}
+		c.session.acknowledge(id)
+
+	case packetPubrec:
+		id, err := decodeAck(body)
+		if nil != err {
+			return err
+		}
+		if c.session.release(id) {
+			c.send(encodeAck(packetPubrel, id), false)
+		}
+
+	case packetPubrel:
+		id, err := decodeAck(body)
+		if nil != err {
+			return err
+		}
+
+		c.session.mu.Lock()
+		delete(c.session.received, id)
+		c.session.mu.Unlock()
+
+		c.send(encodeAck(packetPubcomp, id), false)
+
+	case packetSubscribe:
+		return c.handleSubscribe(body)
+
+	case packetUnsubscribe:
+		id, filters, err := decodeUnsubscribe(body)
+		if nil != err {
+			return err
+		}
+
+		c.session.mu.Lock()
+		for _, filter := range filters {
+			delete(c.session.subscriptions, filter)
+		}
+		c.session.mu.Unlock()
+
+		c.send(encodeAck(packetUnsuback, id), false)
+
+	case packetPingreq:
+		c.send(encodePacket(packetPingresp, 0, nil), false)
+
+	case packetConnect:
+		return errors.New("client sent CONNECT more than once")
+
+	default:
+		return fmt.Errorf("unexpected %s packet", hdr.kind)
+	}
+
+	return
+}
+
+// handlePublish - runs topic handlers for the published message, routes it to subscribers and acknowledges it
+func (c *client) handlePublish(hdr fixedHeader, body []byte) (err error) {
+
+	msg, id, _, err := decodePublish(hdr, body)
+	if nil != err {
+		return err
+	}
+
+	msg.ClientID = c.id
+	msg.Username = c.username
+
+	// MQTT 3.1.1 has no way to refuse a message, so messages the client may not publish are acknowledged as usual but never delivered
+	allowed := c.authorized(ActionPublish, msg.Topic)
+
+	switch msg.QoS {
+	case 0:
+		if allowed {
+			c.l.publish(c.ctx, msg)
+		}
+
+	case 1:
+		if allowed {
+			c.l.publish(c.ctx, msg)
+		}
+		c.send(encodeAck(packetPuback, id), false)
+
+	case 2:
+		// a message with an identifier waiting for PUBREL is a retransmission that was already delivered
+		c.session.mu.Lock()
+		_, duplicate := c.session.received[id]
+		c.session.received[id] = struct{}{}
+		c.session.mu.Unlock()
+
+		if !duplicate && allowed {
+			c.l.publish(c.ctx, msg)
+		}
+		c.send(encodeAck(packetPubrec, id), false)
+	}
+
+	return
+}
+
+// handleSubscribe - adds subscriptions to the session and sends any retained messages matching them
+func (c *client) handleSubscribe(body []byte) (err error) {
+
+	id, subs, err := decodeSubscribe(body)
+	if nil != err {
+		return err
+	}
+
+	codes := make([]byte, len(subs))
+
+	// authorized before locking the session, as authorizers may take a while
+	for i, sub := range subs {
+		if !validTopicFilter(sub.filter) || !c.authorized(ActionSubscribe, sub.filter) {
+			codes[i] = subackFailure
+		}
+	}
+
+	c.session.mu.Lock()
+	for i, sub := range subs {
+		if codes[i] == subackFailure {
+			continue
+		}
+
+		granted := sub.qos
+		if granted > c.l.config.MaxQoS {
+			granted = c.l.config.MaxQoS
+		}
+
+		c.session.subscriptions[sub.filter] = granted
+		codes[i] = granted
+	}
+	c.session.mu.Unlock()
+
+	c.send(encodeSuback(id, codes), false)
+
+	maxQueued := clampQueued(c.l.config.MaxQueued)
+
+	for i, sub := range subs {
+		if codes[i] == subackFailure {
+			continue
+		}
+
+		for _, msg := range c.l.broker.retainedFor(sub.filter) {
+			qos := msg.QoS
+			if codes[i] < qos {
+				qos = codes[i]
+			}
+			c.session.deliver(msg, qos, true, maxQueued)
+		}
+	}
+
+	return
+}
+
+// send - queues a packet to be written to the client, dropping it instead of blocking when requested. Returns false if the packet was not queued
+func (c *client) send(pkt []byte, drop bool) (queued bool) {
+
+	select {
+	case c.out <- pkt:
+		return true
+	case <-c.done:
+		return false
+	default:
+	}
+
+	if drop {
+		return false
+	}
+
+	select {
+	case c.out <- pkt:
+		return true
+	case <-c.done:
+		return false
+	}
+}
+
+// write - writes queued packets to the connection until the client disconnects
+func (c *client) write() {
+	for {
+		select {
+		case pkt := <-c.out:
+			if err := c.writeNow(pkt); nil != err {
+				c.close()
+				return
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// writeNow - writes a packet directly to the connection
+func (c *client) writeNow(pkt []byte) (err error) {
+	c.conn.SetWriteDeadline(time.Now().Add(c.l.config.WriteTimeout))
+	_, err = c.conn.Write(pkt)
+	return
+}
+
+// close - closes the connection, safe to call multiple times
+func (c *client) close() {
+	c.closeOnce.Do(func() {
+		c.cancel()
+		close(c.done)
+		c.conn.Close()
+	})
+}
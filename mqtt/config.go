@@ -0,0 +1,72 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package mqtt
+
+import (
+	"time"
+
+	"github.com/handletec/listener/auth"
+	"github.com/handletec/listener/connlimit"
+	"github.com/handletec/listener/ipfilter"
+	"github.com/handletec/listener/proxyproto"
+	"github.com/handletec/listener/socket"
+)
+
+// Config - listener specific configuration
+type Config struct {
+	ConnectTimeout  time.Duration      // maximum time a client has to send CONNECT after the connection is established
+	ShutdownTimeout time.Duration      // maximum time to wait for clients to finish processing in-flight packets when the listener is stopped
+	WriteTimeout    time.Duration      // maximum time to write a single packet to a client
+	MaxPacketSize   int                // largest packet accepted from clients, in bytes
+	MaxQoS          byte               // highest QoS granted to subscriptions
+	MaxQueued       int                // maximum number of QoS 1 and 2 messages held for each client that are not yet acknowledged
+	IPFilter        *ipfilter.Filter   // (optional) allow and deny lists of client addresses, checked as soon as connections are accepted, so against the load balancer with the PROXY protocol
+	ConnLimit       *connlimit.Config  // (optional) caps the open connections, in total and from each client IP address
+	ProxyProtocol   *proxyproto.Config // (optional) read the address of the original client from the PROXY header sent by trusted load balancers
+	Listen          socket.ListenFunc  // (optional) binds the listener instead of `net.Listen`, e.g. an in-memory listener for tests
+	handler         *Handler
+	authn           auth.Authenticator
+	authz           auth.Authorizer
+}
+
+// NewConfig - creates new instance of config
+func NewConfig() (cfg *Config) {
+	cfg = new(Config)
+
+	// set default configuration
+	cfg.ConnectTimeout = time.Duration(10 * time.Second)
+	cfg.ShutdownTimeout = time.Duration(30 * time.Second)
+	cfg.WriteTimeout = time.Duration(10 * time.Second)
+	cfg.MaxPacketSize = 1 << 20 // 1 MiB
+	cfg.MaxQoS = 2
+	cfg.MaxQueued = 1000
+	cfg.handler = nil // topic handlers are optional, the broker works without them
+
+	return
+}
+
+// SetHandler - sets the handlers to run when clients publish to matching topics
+func (cfg *Config) SetHandler(handler *Handler) (err error) {
+	cfg.handler = handler
+	return
+}
+
+// SetAuth - sets the authenticator run for every CONNECT packet and the authorizer run for every topic clients publish or subscribe to,
+// either of them may be nil to skip that step. The username and password of the client are given to the authenticator as metadata
+func (cfg *Config) SetAuth(authn auth.Authenticator, authz auth.Authorizer) {
+	cfg.authn = authn
+	cfg.authz = authz
+}
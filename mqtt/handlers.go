@@ -0,0 +1,111 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package mqtt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Message - application message published to the broker
+type Message struct {
+	Topic    string
+	Payload  []byte
+	QoS      byte
+	Retain   bool
+	ClientID string // client that published the message, empty when published by the server
+	Username string // username the publishing client connected with
+}
+
+// HandlerFunc - function run when a client publishes to a matching topic, returning an error prevents the message from being delivered to subscribers
+type HandlerFunc func(ctx context.Context, msg *Message) (err error)
+
+// Middleware - wraps a handler to run common tasks such as authorization or validation before the handler is called
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// route - topic filter and the handler, wrapped in its middlewares, to run for it
+type route struct {
+	filter string
+	hFn    HandlerFunc
+}
+
+// Handler - topic filters and the functions to run when messages are published to them
+type Handler struct {
+	middlewares []Middleware
+	routes      []route
+	mu          sync.RWMutex // protects routes
+}
+
+// NewHandler - create new instance of handler, with middlewares run for every topic before the topic specific middlewares
+func NewHandler(middlewares ...Middleware) (handler *Handler) {
+	handler = new(Handler)
+	handler.middlewares = middlewares
+
+	return handler
+}
+
+// Set - sets a handler to a given topic filter, which may contain the `+` and `#` wildcards
+func (handler *Handler) Set(filter string, hFn HandlerFunc, middlewares ...Middleware) (err error) {
+
+	if len(filter) == 0 {
+		return errors.New("MQTT sethandler: topic filter cannot be left blank")
+	}
+
+	if !validTopicFilter(filter) {
+		return fmt.Errorf("MQTT sethandler: invalid topic filter '%s'", filter)
+	}
+
+	if nil == hFn {
+		return errors.New("MQTT sethandler: handler function cannot be nil")
+	}
+
+	// add custom middlewares for this topic, could be authorization, payload validation, etc
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		hFn = middlewares[i](hFn)
+	}
+
+	for i := len(handler.middlewares) - 1; i >= 0; i-- {
+		hFn = handler.middlewares[i](hFn)
+	}
+
+	handler.mu.Lock()
+	handler.routes = append(handler.routes, route{filter: filter, hFn: hFn})
+	handler.mu.Unlock()
+
+	return
+}
+
+// serve - runs all handlers matching the message topic, stopping at the first error
+func (handler *Handler) serve(ctx context.Context, msg *Message) (err error) {
+
+	handler.mu.RLock()
+	routes := handler.routes
+	handler.mu.RUnlock()
+
+	for _, r := range routes {
+		if !matchTopic(r.filter, msg.Topic) {
+			continue
+		}
+
+		if err = r.hFn(ctx, msg); nil != err {
+			return fmt.Errorf("handler '%s': %w", r.filter, err)
+		}
+	}
+
+	return
+}
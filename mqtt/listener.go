@@ -0,0 +1,465 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package mqtt
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/handletec/listener/describe"
+	"github.com/handletec/listener/internal/accept"
+	"github.com/handletec/listener/lifecycle"
+	"github.com/handletec/listener/socket"
+	slogformatter "github.com/samber/slog-formatter"
+)
+
+const (
+	// DefaultAddr - listen on all IPv4 and IPv6 interfaces
+	DefaultAddr = "[::]"
+
+	// DefaultPort - default port to listen on
+	DefaultPort = 1883
+
+	// DefaultTLSPort - default port to listen on when TLS is configured
+	DefaultTLSPort = 8883
+
+	// EphemeralPort - let the OS pick any available port, use `Addr` to find the port that was bound
+	EphemeralPort = -1
+)
+
+// Listener - implementation of MQTT 3.1.1 broker listener
+type Listener struct {
+	lifecycle.Lifecycle // state, hooks and readiness, reported through `Status`, `Subscribe` and `Ready`
+
+	name      string
+	address   string
+	port      int
+	tlsConfig *tls.Config
+	logger    *slog.Logger
+	config    *Config
+	broker    *broker
+	ln        net.Listener
+	addr      net.Addr
+	clients   map[*client]struct{}
+	wg        sync.WaitGroup // tracks running clients
+	closing   atomic.Bool
+	mu        sync.Mutex // protects ln, addr and clients
+}
+
+// New - create new instance of the MQTT listener
+func New() (l *Listener) {
+	l = new(Listener)
+	return
+}
+
+// Name - returns the name of this listener
+func (l *Listener) Name() (str string) {
+	if len(l.name) == 0 {
+		return "MQTT"
+	}
+
+	return l.name
+}
+
+// SetName - sets a custom name for this listener, used in logs and to match sockets passed through socket activation
+func (l *Listener) SetName(name string) {
+	l.name = name
+}
+
+// Init - initializes this listener with any necessary configuration parameters
+func (l *Listener) Init(logger *slog.Logger, address string, port int, tlsConfig *tls.Config) (err error) {
+
+	defer func() {
+		if nil != err {
+			l.SetState(l.Name(), lifecycle.Failed, err)
+			return
+		}
+		l.SetState(l.Name(), lifecycle.Initialized, nil)
+	}()
+
+	if len(address) == 0 {
+		address = DefaultAddr // if no address is given we have it listen on all IPv4 and IPv6 interfaces
+	}
+
+	l.tlsConfig = tlsConfig
+
+	switch {
+	case port == 0 && l.tlsEnabled():
+		port = DefaultTLSPort // MQTT over TLS has its own well known port
+	case port == 0:
+		port = DefaultPort // default port if none is given
+	case port == EphemeralPort:
+		port = 0 // the OS assigns a free port when binding to port 0
+	case port < 0 || port > 65535:
+		return fmt.Errorf("MQTT init: invalid port %d", port)
+	}
+
+	l.address = address
+	l.port = port
+
+	l.mu.Lock()
+	l.addr = nil
+	l.mu.Unlock()
+
+	if nil == logger {
+		// if no logger is given, create a new instance
+		logger = slog.New(
+			slogformatter.NewFormatterHandler(
+				slogformatter.TimezoneConverter(time.UTC),
+				slogformatter.TimeFormatter(time.RFC3339, nil),
+			)(
+				slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{}),
+			),
+		)
+	}
+
+	l.logger = logger
+
+	if nil == l.config {
+		// if no configuration is set, init a new one with sane default values
+		l.config = NewConfig()
+	}
+
+	if nil == l.broker {
+		l.broker = newBroker()
+	}
+
+	if err = l.Hooks().Run(context.Background(), lifecycle.EventInit); nil != err {
+		return fmt.Errorf("MQTT init: %w", err)
+	}
+
+	return
+}
+
+// SetConfig - sets configuration details for this listener
+func (l *Listener) SetConfig(config any) (err error) {
+	cfg, ok := config.(*Config)
+	if !ok {
+		return fmt.Errorf("MQTT setconfig: expected *mqtt.Config, received %T", config)
+	}
+
+	l.config = cfg
+	return
+}
+
+// Config - returns the configuration in use by this listener
+func (l *Listener) Config() (config any) {
+	return l.config
+}
+
+// Start - starts this listener and blocks until it stops, cancelling ctx gracefully shuts down the listener
+func (l *Listener) Start(ctx context.Context) (err error) {
+	l.logger.Info("listener starting", "listener", l.Name())
+	l.SetState(l.Name(), lifecycle.Starting, nil)
+
+	defer func() {
+		if nil != err {
+			l.SetState(l.Name(), lifecycle.Failed, err)
+		}
+	}()
+
+	if nil == l.broker {
+		return errors.New("MQTT start: listener not initialized")
+	}
+
+	chain := accept.Chain{IPFilter: l.config.IPFilter, ConnLimit: l.config.ConnLimit, ProxyProtocol: l.config.ProxyProtocol}
+	if err = chain.Validate(); nil != err {
+		return fmt.Errorf("start mqtt: %w", err)
+	}
+
+	if err = l.Hooks().Run(ctx, lifecycle.EventBeforeStart); nil != err {
+		return fmt.Errorf("start mqtt: %w", err)
+	}
+
+	ln, err := l.listen()
+	if nil != err {
+		return fmt.Errorf("start mqtt: %w", err)
+	}
+
+	// allow the socket to be passed to a new process during a binary upgrade
+	socket.Track(l.Name(), ln)
+	defer socket.Untrack(ln)
+
+	tlsEnabled := l.tlsEnabled()
+	scheme := "mqtt"
+	if tlsEnabled {
+		scheme = "mqtts"
+	}
+
+	if tlsEnabled {
+		chain.TLS = l.tlsConfig
+	}
+
+	ln = chain.Wrap(ln, l.logger.With("listener", l.Name()))
+
+	l.closing.Store(false)
+
+	l.mu.Lock()
+	l.ln = ln
+	l.addr = ln.Addr()
+	l.clients = make(map[*client]struct{})
+	l.mu.Unlock()
+
+	l.logger.Info("listener started", "listener", l.Name(), "address", scheme+"://"+ln.Addr().String(), "tls", strconv.FormatBool(tlsEnabled))
+
+	acceptErr := make(chan error, 1)
+	go func() {
+		acceptErr <- l.accept(ln)
+	}()
+
+	if err = l.Started(ctx, l.Name()); nil != err {
+		// a started hook failed, disconnect the clients that connected meanwhile so none of them is served by a broker half set up
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), l.config.ShutdownTimeout)
+		defer cancel()
+
+		l.Shutdown(shutdownCtx)
+		<-acceptErr
+
+		return fmt.Errorf("start mqtt: %w", err)
+	}
+
+	select {
+	case err = <-acceptErr:
+		if nil != err {
+			// the accept loop failed, e.g. the socket was closed underneath it, the stopped hooks still run to release what the started hooks acquired
+			err = errors.Join(err, l.Hooks().Run(context.WithoutCancel(ctx), lifecycle.EventStopped))
+		}
+	case <-ctx.Done():
+		// the caller no longer wants this listener running, drain in-flight packets before returning
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), l.config.ShutdownTimeout)
+		defer cancel()
+
+		err = l.Shutdown(shutdownCtx)
+		<-acceptErr
+	}
+
+	if nil != err {
+		return fmt.Errorf("start mqtt: %w", err)
+	}
+
+	return nil
+}
+
+// accept - accepts connections until the listener is closed
+func (l *Listener) accept(ln net.Listener) (err error) {
+
+	return accept.Serve(ln, l.logger.With("listener", l.Name()), l.isClosing, func(conn net.Conn) (ok bool) {
+
+		c := newClient(l, conn)
+
+		l.mu.Lock()
+		if l.isClosing() {
+			l.mu.Unlock()
+			conn.Close()
+			return false
+		}
+		l.clients[c] = struct{}{}
+		l.wg.Add(1)
+		l.mu.Unlock()
+
+		go func() {
+			defer l.wg.Done()
+			defer func() {
+				l.mu.Lock()
+				delete(l.clients, c)
+				l.mu.Unlock()
+			}()
+
+			c.serve()
+		}()
+
+		return true
+	})
+}
+
+// listen - binds with the listen function of the configuration if there is one, otherwise uses the socket passed by the supervisor
+// under this listener's name if there is one, otherwise binds to the configured address and port
+func (l *Listener) listen() (ln net.Listener, err error) {
+
+	address := net.JoinHostPort(strings.Trim(l.address, "[]"), strconv.Itoa(l.port))
+
+	if nil != l.config.Listen {
+		return l.config.Listen("tcp", address)
+	}
+
+	ln, err = socket.Listener(l.Name())
+	if nil != err {
+		return nil, err
+	}
+
+	if nil != ln {
+		l.logger.Info("listener using activated socket", "listener", l.Name(), "address", ln.Addr().String())
+		return
+	}
+
+	return net.Listen("tcp", address)
+}
+
+// Shutdown - stops accepting new connections and waits for clients to finish processing their current packet or for ctx to expire, whichever comes first
+func (l *Listener) Shutdown(ctx context.Context) (err error) {
+
+	l.mu.Lock()
+	ln := l.ln
+	l.ln = nil
+	l.mu.Unlock()
+
+	if nil == ln {
+		return // listener was never started, nothing to shutdown
+	}
+
+	l.logger.Info("listener shutting down", "listener", l.Name())
+	l.SetState(l.Name(), lifecycle.Draining, nil)
+
+	// deregister from e.g. service discovery while clients are still connected, so they reconnect to another broker
+	hookErr := l.Hooks().Run(ctx, lifecycle.EventShutdown)
+
+	l.closing.Store(true)
+	ln.Close()
+
+	// interrupt every read, including packets only partly received which are dropped, packets already decoded finish being handled
+	l.mu.Lock()
+	clients := make([]*client, 0, len(l.clients))
+	for c := range l.clients {
+		clients = append(clients, c)
+		c.conn.SetReadDeadline(time.Now())
+	}
+	l.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		l.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		for _, c := range clients {
+			c.close()
+		}
+		<-done
+		err = fmt.Errorf("shutdown mqtt: %w", ctx.Err())
+	}
+
+	l.logger.Info("listener stopped", "listener", l.Name())
+
+	// clients finished their in-flight packets or were disconnected, stopped hooks run either way
+	err = l.Stopped(ctx, l.Name(), errors.Join(err, hookErr))
+
+	return
+}
+
+// Publish - publishes a message from the server to all subscribed clients, topic handlers are not run for these messages
+func (l *Listener) Publish(topic string, payload []byte, qos byte, retain bool) (err error) {
+
+	if nil == l.broker {
+		return errors.New("MQTT publish: listener not initialized")
+	}
+
+	if !validTopicName(topic) {
+		return fmt.Errorf("MQTT publish: invalid topic name '%s'", topic)
+	}
+
+	if qos > 2 {
+		return fmt.Errorf("MQTT publish: invalid QoS %d", qos)
+	}
+
+	msg := &Message{
+		Topic:   topic,
+		Payload: payload,
+		QoS:     qos,
+		Retain:  retain,
+	}
+
+	l.deliver(msg)
+
+	return
+}
+
+// publish - runs the topic handlers for a message published by a client, then delivers it if none of the handlers rejected it
+func (l *Listener) publish(ctx context.Context, msg *Message) {
+
+	if nil != l.config.handler {
+		if err := l.config.handler.serve(ctx, msg); nil != err {
+			l.logger.Warn("message rejected", "listener", l.Name(), "client", msg.ClientID, "topic", msg.Topic, "error", err)
+			return
+		}
+	}
+
+	l.deliver(msg)
+}
+
+// deliver - retains the message if requested and routes it to all subscribers
+func (l *Listener) deliver(msg *Message) {
+
+	if msg.Retain {
+		l.broker.retain(msg)
+	}
+
+	if dropped := l.broker.route(msg, clampQueued(l.config.MaxQueued)); dropped > 0 {
+		l.logger.Warn("message dropped for slow or offline clients", "listener", l.Name(), "topic", msg.Topic, "clients", dropped)
+	}
+}
+
+// Describe - describes the address and TLS settings of this listener
+func (l *Listener) Describe() (d describe.Description) {
+	d.Name = l.Name()
+	d.Protocol = "MQTT"
+	d.Address = describe.Address(l.Addr(), l.address, l.port)
+	d.TLS = describe.TLSOf(l.tlsConfig)
+
+	return
+}
+
+// Addr - returns the address this listener is bound to, or nil if it has not been started
+func (l *Listener) Addr() (addr net.Addr) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.addr
+}
+
+// Status - returns the lifecycle state of this MQTT listener under its current name, which may have been changed by `SetName`
+func (l *Listener) Status() (status lifecycle.Status) {
+	status = l.Lifecycle.Status()
+	status.Name = l.Name()
+
+	return
+}
+
+// isClosing - determines if the listener is shutting down
+func (l *Listener) isClosing() (closing bool) {
+	return l.closing.Load()
+}
+
+// tlsEnabled - determines if the given TLS configuration is able to serve certificates
+func (l *Listener) tlsEnabled() (enabled bool) {
+	if nil == l.tlsConfig {
+		return false
+	}
+
+	return len(l.tlsConfig.Certificates) > 0 || nil != l.tlsConfig.GetCertificate || nil != l.tlsConfig.GetConfigForClient
+}
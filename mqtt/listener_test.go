@@ -0,0 +1,124 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package mqtt_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/handletec/listener/listenertest"
+	"github.com/handletec/listener/mqtt"
+)
+
+// mqttString - encodes a string prefixed with its length
+func mqttString(s string) []byte {
+	return append([]byte{byte(len(s) >> 8), byte(len(s))}, s...)
+}
+
+// packet - encodes a packet with a remaining length below 128 bytes
+func packet(kind byte, body []byte) []byte {
+	return append([]byte{kind, byte(len(body))}, body...)
+}
+
+// connect - sends CONNECT with the credentials and returns the CONNACK return code
+func connect(t *testing.T, conn net.Conn, r *bufio.Reader, username, password string) (code byte) {
+	t.Helper()
+
+	body := append(mqttString("MQTT"), 4, 0xC2, 0, 60) // clean session with username and password
+	body = append(body, mqttString("device-1")...)
+	body = append(body, mqttString(username)...)
+	body = append(body, mqttString(password)...)
+
+	if _, err := conn.Write(packet(0x10, body)); nil != err {
+		t.Fatal(err)
+	}
+
+	connack := make([]byte, 4)
+	if _, err := io.ReadFull(r, connack); nil != err {
+		t.Fatalf("read CONNACK: %v", err)
+	}
+
+	return connack[3]
+}
+
+func TestPublishAndShutdown(t *testing.T) {
+
+	l := mqtt.New()
+	if err := l.SetConfig(mqtt.NewConfig()); nil != err {
+		t.Fatal(err)
+	}
+
+	opts := listenertest.NewOptions()
+	opts.InMemory = true
+
+	s := listenertest.Start(t, l, opts)
+
+	conn := s.Dial()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	r := bufio.NewReader(conn)
+
+	if code := connect(t, conn, r, "alice", "secret"); code != 0 {
+		t.Fatalf("CONNACK code = %d, expected 0", code)
+	}
+
+	body := append([]byte{0, 1}, mqttString("sensors/#")...)
+	body = append(body, 0)
+	if _, err := conn.Write(packet(0x82, body)); nil != err {
+		t.Fatal(err)
+	}
+
+	suback := make([]byte, 5)
+	if _, err := io.ReadFull(r, suback); nil != err {
+		t.Fatalf("read SUBACK: %v", err)
+	}
+
+	// messages published by the server reach the subscribed client
+	if err := l.Publish("sensors/1", []byte("21"), 0, false); nil != err {
+		t.Fatalf("Publish() = %v", err)
+	}
+
+	expected := packet(0x30, append(mqttString("sensors/1"), "21"...))
+	publish := make([]byte, len(expected))
+	if _, err := io.ReadFull(r, publish); nil != err {
+		t.Fatalf("read PUBLISH: %v", err)
+	}
+
+	if !bytes.Equal(publish, expected) {
+		t.Fatalf("PUBLISH = % x, expected % x", publish, expected)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := l.Shutdown(ctx); nil != err {
+		t.Fatalf("Shutdown() = %v", err)
+	}
+
+	// clients waiting for their next packet are disconnected once the listener is shut down
+	if _, err := r.ReadByte(); !errors.Is(err, io.EOF) {
+		t.Errorf("idle client still connected after shutdown: %v", err)
+	}
+
+	if err := l.Shutdown(ctx); nil != err {
+		t.Errorf("second Shutdown() = %v, expected nil", err)
+	}
+}
@@ -0,0 +1,450 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package mqtt
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+// packetType - MQTT control packet types
+type packetType byte
+
+const (
+	packetConnect packetType = iota + 1
+	packetConnack
+	packetPublish
+	packetPuback
+	packetPubrec
+	packetPubrel
+	packetPubcomp
+	packetSubscribe
+	packetSuback
+	packetUnsubscribe
+	packetUnsuback
+	packetPingreq
+	packetPingresp
+	packetDisconnect
+)
+
+const (
+	// maxRemainingLength - largest remaining length that can be encoded in 4 bytes
+	maxRemainingLength = 268435455
+
+	// flagsPubrel - fixed header flags required for PUBREL, SUBSCRIBE and UNSUBSCRIBE
+	flagsPubrel = 0x02
+)
+
+// CONNACK return codes
+const (
+	connackAccepted byte = iota
+	connackBadProtocol
+	connackIdentifierRejected
+	connackServerUnavailable
+	connackBadCredentials
+	connackNotAuthorized
+)
+
+// subackFailure - SUBACK return code for a rejected subscription
+const subackFailure = 0x80
+
+var (
+	errMalformed     = errors.New("malformed packet")
+	errPacketTooLong = errors.New("packet exceeds maximum size")
+)
+
+func (pt packetType) String() (str string) {
+
+	typeName := []string{"UNKNOWN", "CONNECT", "CONNACK", "PUBLISH", "PUBACK", "PUBREC", "PUBREL", "PUBCOMP", "SUBSCRIBE", "SUBACK", "UNSUBSCRIBE", "UNSUBACK", "PINGREQ", "PINGRESP", "DISCONNECT"}
+	typeInt := int(pt)
+
+	if typeInt < 0 || typeInt >= len(typeName) {
+		typeInt = 0
+	}
+
+	return typeName[typeInt]
+}
+
+// fixedHeader - first part of every MQTT control packet
+type fixedHeader struct {
+	kind   packetType
+	flags  byte
+	length int
+}
+
+// readPacket - reads a single control packet, rejecting packets larger than maxSize
+func readPacket(r *bufio.Reader, maxSize int) (hdr fixedHeader, body []byte, err error) {
+
+	b, err := r.ReadByte()
+	if nil != err {
+		return hdr, nil, err
+	}
+
+	hdr.kind = packetType(b >> 4)
+	hdr.flags = b & 0x0f
+
+	multiplier := 1
+	for i := 0; ; i++ {
+		if i == 4 {
+			return hdr, nil, errMalformed // remaining length uses at most 4 bytes
+		}
+
+		b, err = r.ReadByte()
+		if nil != err {
+			return hdr, nil, err
+		}
+
+		hdr.length += int(b&0x7f) * multiplier
+		multiplier *= 128
+
+		if b&0x80 == 0 {
+			break
+		}
+	}
+
+	if hdr.length > maxSize {
+		return hdr, nil, errPacketTooLong
+	}
+
+	body = make([]byte, hdr.length)
+	if _, err = io.ReadFull(r, body); nil != err {
+		return hdr, nil, err
+	}
+
+	return
+}
+
+// encodePacket - builds a complete control packet from the given type, flags and body
+func encodePacket(kind packetType, flags byte, body []byte) (pkt []byte) {
+
+	pkt = make([]byte, 0, len(body)+5)
+	pkt = append(pkt, byte(kind)<<4|flags&0x0f)
+
+	length := len(body)
+	for {
+		b := byte(length % 128)
+		length /= 128
+		if length > 0 {
+			b |= 0x80
+		}
+		pkt = append(pkt, b)
+		if length == 0 {
+			break
+		}
+	}
+
+	return append(pkt, body...)
+}
+
+// packetReader - helper for decoding the variable header and payload of a packet
+type packetReader struct {
+	buf []byte
+	err error
+}
+
+// byte - reads a single byte
+func (pr *packetReader) byte() (b byte) {
+	if nil != pr.err {
+		return
+	}
+	if len(pr.buf) < 1 {
+		pr.err = errMalformed
+		return
+	}
+
+	b = pr.buf[0]
+	pr.buf = pr.buf[1:]
+	return
+}
+
+// uint16 - reads a two byte big endian integer
+func (pr *packetReader) uint16() (v uint16) {
+	if nil != pr.err {
+		return
+	}
+	if len(pr.buf) < 2 {
+		pr.err = errMalformed
+		return
+	}
+
+	v = binary.BigEndian.Uint16(pr.buf)
+	pr.buf = pr.buf[2:]
+	return
+}
+
+// binary - reads length prefixed binary data
+func (pr *packetReader) binary() (b []byte) {
+	length := int(pr.uint16())
+	if nil != pr.err {
+		return
+	}
+	if len(pr.buf) < length {
+		pr.err = errMalformed
+		return
+	}
+
+	b = make([]byte, length)
+	copy(b, pr.buf)
+	pr.buf = pr.buf[length:]
+	return
+}
+
+// string - reads a length prefixed UTF-8 string
+func (pr *packetReader) string() (str string) {
+	b := pr.binary()
+	if nil != pr.err {
+		return
+	}
+	if !utf8.Valid(b) {
+		pr.err = fmt.Errorf("%w: invalid UTF-8 string", errMalformed)
+		return
+	}
+
+	return string(b)
+}
+
+// remaining - returns all bytes that have not been read
+func (pr *packetReader) remaining() (b []byte) {
+	b = make([]byte, len(pr.buf))
+	copy(b, pr.buf)
+	pr.buf = nil
+	return
+}
+
+// appendUint16 - appends a two byte big endian integer
+func appendUint16(b []byte, v uint16) []byte {
+	return binary.BigEndian.AppendUint16(b, v)
+}
+
+// appendBinary - appends length prefixed binary data
+func appendBinary(b []byte, v []byte) []byte {
+	b = appendUint16(b, uint16(len(v)))
+	return append(b, v...)
+}
+
+// connectPacket - decoded CONNECT packet
+type connectPacket struct {
+	protocol     string
+	level        byte
+	cleanSession bool
+	keepAlive    uint16
+	clientID     string
+	will         *Message
+	username     string
+	password     []byte
+}
+
+// decodeConnect - decodes a CONNECT packet, returning the CONNACK code to respond with when the protocol is not supported
+func decodeConnect(body []byte) (cp *connectPacket, code byte, err error) {
+
+	pr := &packetReader{buf: body}
+	cp = new(connectPacket)
+
+	cp.protocol = pr.string()
+	cp.level = pr.byte()
+	flags := pr.byte()
+	cp.keepAlive = pr.uint16()
+
+	if nil != pr.err {
+		return nil, 0, pr.err
+	}
+
+	// MQTT 3.1.1 uses "MQTT" level 4, MQTT 3.1 uses "MQIsdp" level 3
+	if !(cp.protocol == "MQTT" && cp.level == 4) && !(cp.protocol == "MQIsdp" && cp.level == 3) {
+		return nil, connackBadProtocol, fmt.Errorf("unsupported protocol %s level %d", cp.protocol, cp.level)
+	}
+
+	if flags&0x01 != 0 {
+		return nil, 0, fmt.Errorf("%w: reserved connect flag set", errMalformed)
+	}
+
+	cp.cleanSession = flags&0x02 != 0
+	hasWill := flags&0x04 != 0
+	willQoS := (flags >> 3) & 0x03
+	willRetain := flags&0x20 != 0
+	hasPassword := flags&0x40 != 0
+	hasUsername := flags&0x80 != 0
+
+	if willQoS > 2 || (!hasWill && (willQoS != 0 || willRetain)) {
+		return nil, 0, fmt.Errorf("%w: invalid will flags", errMalformed)
+	}
+
+	cp.clientID = pr.string()
+
+	if hasWill {
+		cp.will = &Message{
+			Topic:  pr.string(),
+			QoS:    willQoS,
+			Retain: willRetain,
+		}
+		cp.will.Payload = pr.binary()
+	}
+
+	if hasUsername {
+		cp.username = pr.string()
+	}
+
+	if hasPassword {
+		cp.password = pr.binary()
+	}
+
+	if nil != pr.err {
+		return nil, 0, pr.err
+	}
+
+	if nil != cp.will && !validTopicName(cp.will.Topic) {
+		return nil, 0, fmt.Errorf("%w: invalid will topic", errMalformed)
+	}
+
+	return
+}
+
+// encodeConnack - builds a CONNACK packet
+func encodeConnack(sessionPresent bool, code byte) []byte {
+	var flags byte
+	if sessionPresent {
+		flags = 0x01
+	}
+	return encodePacket(packetConnack, 0, []byte{flags, code})
+}
+
+// decodePublish - decodes a PUBLISH packet
+func decodePublish(hdr fixedHeader, body []byte) (msg *Message, packetID uint16, dup bool, err error) {
+
+	pr := &packetReader{buf: body}
+
+	msg = &Message{
+		QoS:    (hdr.flags >> 1) & 0x03,
+		Retain: hdr.flags&0x01 != 0,
+	}
+	dup = hdr.flags&0x08 != 0
+
+	if msg.QoS > 2 {
+		return nil, 0, false, fmt.Errorf("%w: invalid QoS", errMalformed)
+	}
+
+	msg.Topic = pr.string()
+	if msg.QoS > 0 {
+		packetID = pr.uint16()
+		if 0 == packetID && nil == pr.err {
+			pr.err = fmt.Errorf("%w: packet identifier cannot be 0", errMalformed)
+		}
+	}
+	msg.Payload = pr.remaining()
+
+	if nil != pr.err {
+		return nil, 0, false, pr.err
+	}
+
+	if !validTopicName(msg.Topic) {
+		return nil, 0, false, fmt.Errorf("%w: invalid topic name '%s'", errMalformed, msg.Topic)
+	}
+
+	return
+}
+
+// encodePublish - builds a PUBLISH packet
+func encodePublish(msg *Message, qos byte, retain bool, packetID uint16, dup bool) []byte {
+
+	flags := qos << 1
+	if retain {
+		flags |= 0x01
+	}
+	if dup {
+		flags |= 0x08
+	}
+
+	body := make([]byte, 0, len(msg.Topic)+len(msg.Payload)+4)
+	body = appendBinary(body, []byte(msg.Topic))
+	if qos > 0 {
+		body = appendUint16(body, packetID)
+	}
+	body = append(body, msg.Payload...)
+
+	return encodePacket(packetPublish, flags, body)
+}
+
+// encodeAck - builds packets consisting of only a packet identifier (PUBACK, PUBREC, PUBREL, PUBCOMP, UNSUBACK)
+func encodeAck(kind packetType, packetID uint16) []byte {
+	var flags byte
+	if kind == packetPubrel {
+		flags = flagsPubrel
+	}
+	return encodePacket(kind, flags, appendUint16(nil, packetID))
+}
+
+// decodeAck - decodes packets consisting of only a packet identifier
+func decodeAck(body []byte) (packetID uint16, err error) {
+	if len(body) != 2 {
+		return 0, errMalformed
+	}
+	return binary.BigEndian.Uint16(body), nil
+}
+
+// subscription - topic filter and requested QoS within a SUBSCRIBE packet
+type subscription struct {
+	filter string
+	qos    byte
+}
+
+// decodeSubscribe - decodes a SUBSCRIBE packet
+func decodeSubscribe(body []byte) (packetID uint16, subs []subscription, err error) {
+
+	pr := &packetReader{buf: body}
+	packetID = pr.uint16()
+
+	for nil == pr.err && len(pr.buf) > 0 {
+		sub := subscription{filter: pr.string()}
+		sub.qos = pr.byte()
+		if sub.qos&0xfc != 0 && nil == pr.err {
+			pr.err = fmt.Errorf("%w: invalid requested QoS", errMalformed)
+		}
+		subs = append(subs, sub)
+	}
+
+	if nil == pr.err && len(subs) == 0 {
+		pr.err = fmt.Errorf("%w: subscribe without topic filters", errMalformed)
+	}
+
+	return packetID, subs, pr.err
+}
+
+// encodeSuback - builds a SUBACK packet
+func encodeSuback(packetID uint16, codes []byte) []byte {
+	body := appendUint16(make([]byte, 0, len(codes)+2), packetID)
+	return encodePacket(packetSuback, 0, append(body, codes...))
+}
+
+// decodeUnsubscribe - decodes an UNSUBSCRIBE packet
+func decodeUnsubscribe(body []byte) (packetID uint16, filters []string, err error) {
+
+	pr := &packetReader{buf: body}
+	packetID = pr.uint16()
+
+	for nil == pr.err && len(pr.buf) > 0 {
+		filters = append(filters, pr.string())
+	}
+
+	if nil == pr.err && len(filters) == 0 {
+		pr.err = fmt.Errorf("%w: unsubscribe without topic filters", errMalformed)
+	}
+
+	return packetID, filters, pr.err
+}
@@ -0,0 +1,262 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package mqtt
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestRemainingLength(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		encoded []byte // remaining length as sent on the wire
+		length  int
+	}{
+		{name: "zero", encoded: []byte{0x00}, length: 0},
+		{name: "one byte maximum", encoded: []byte{0x7f}, length: 127},
+		{name: "two bytes minimum", encoded: []byte{0x80, 0x01}, length: 128},
+		{name: "two bytes maximum", encoded: []byte{0xff, 0x7f}, length: 16383},
+		{name: "three bytes minimum", encoded: []byte{0x80, 0x80, 0x01}, length: 16384},
+		{name: "three bytes maximum", encoded: []byte{0xff, 0xff, 0x7f}, length: 2097151},
+		{name: "four bytes minimum", encoded: []byte{0x80, 0x80, 0x80, 0x01}, length: 2097152},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := bytes.Repeat([]byte{0xaa}, tt.length)
+
+			pkt := encodePacket(packetPublish, 0x03, body)
+			if want := append([]byte{0x33}, tt.encoded...); !bytes.Equal(pkt[:len(want)], want) {
+				t.Fatalf("encoded header = % x, want % x", pkt[:len(want)], want)
+			}
+
+			hdr, got, err := readPacket(bufio.NewReader(bytes.NewReader(pkt)), tt.length)
+			if nil != err {
+				t.Fatalf("readPacket(): %v", err)
+			}
+
+			if hdr.kind != packetPublish || hdr.flags != 0x03 || hdr.length != tt.length || !bytes.Equal(got, body) {
+				t.Errorf("readPacket() = %s flags %#x length %d, want %s flags 0x3 length %d", hdr.kind, hdr.flags, hdr.length, packetPublish, tt.length)
+			}
+		})
+	}
+}
+
+func TestReadPacketErrors(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		pkt     []byte
+		maxSize int
+		wantErr error
+	}{
+		{name: "remaining length over four bytes", pkt: []byte{0x30, 0x80, 0x80, 0x80, 0x80, 0x01}, maxSize: 1 << 30, wantErr: errMalformed},
+		{name: "larger than the maximum size", pkt: []byte{0x30, 0x05, 1, 2, 3, 4, 5}, maxSize: 4, wantErr: errPacketTooLong},
+		{name: "truncated remaining length", pkt: []byte{0x30, 0x80}, maxSize: 1024, wantErr: io.EOF},
+		{name: "truncated body", pkt: []byte{0x30, 0x05, 1, 2}, maxSize: 1024, wantErr: io.ErrUnexpectedEOF},
+		{name: "empty", pkt: nil, maxSize: 1024, wantErr: io.EOF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := readPacket(bufio.NewReader(bytes.NewReader(tt.pkt)), tt.maxSize)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("readPacket() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// connectBody - builds the body of a CONNECT packet with the given protocol, level and flags, followed by the payload fields
+func connectBody(protocol string, level, flags byte, fields ...[]byte) (body []byte) {
+	body = appendBinary(nil, []byte(protocol))
+	body = append(body, level, flags, 0, 60) // keep alive of 60 seconds
+
+	for _, f := range fields {
+		body = appendBinary(body, f)
+	}
+
+	return
+}
+
+func TestDecodeConnect(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		body     []byte
+		wantCode byte
+		wantErr  bool
+		check    func(t *testing.T, cp *connectPacket)
+	}{
+		{
+			name: "MQTT 3.1.1 clean session",
+			body: connectBody("MQTT", 4, 0x02, []byte("device-1")),
+			check: func(t *testing.T, cp *connectPacket) {
+				if cp.clientID != "device-1" || !cp.cleanSession || cp.keepAlive != 60 || nil != cp.will {
+					t.Errorf("decoded %+v", cp)
+				}
+			},
+		},
+		{
+			name: "MQTT 3.1",
+			body: connectBody("MQIsdp", 3, 0x00, []byte("device-1")),
+			check: func(t *testing.T, cp *connectPacket) {
+				if cp.cleanSession {
+					t.Error("clean session set without its flag")
+				}
+			},
+		},
+		{
+			name: "username and password",
+			body: connectBody("MQTT", 4, 0xc2, []byte("device-1"), []byte("alice"), []byte("secret")),
+			check: func(t *testing.T, cp *connectPacket) {
+				if cp.username != "alice" || string(cp.password) != "secret" {
+					t.Errorf("username %q password %q", cp.username, cp.password)
+				}
+			},
+		},
+		{
+			name: "will with QoS 1 and retain",
+			body: connectBody("MQTT", 4, 0x2e, []byte("device-1"), []byte("devices/1/status"), []byte("offline")),
+			check: func(t *testing.T, cp *connectPacket) {
+				if nil == cp.will || cp.will.Topic != "devices/1/status" || string(cp.will.Payload) != "offline" || cp.will.QoS != 1 || !cp.will.Retain {
+					t.Errorf("will %+v", cp.will)
+				}
+			},
+		},
+		{name: "unsupported protocol level", body: connectBody("MQTT", 5, 0x02, []byte("device-1")), wantCode: connackBadProtocol, wantErr: true},
+		{name: "unsupported protocol name", body: connectBody("MQTX", 4, 0x02, []byte("device-1")), wantCode: connackBadProtocol, wantErr: true},
+		{name: "reserved flag", body: connectBody("MQTT", 4, 0x03, []byte("device-1")), wantErr: true},
+		{name: "will QoS 3", body: connectBody("MQTT", 4, 0x1e, []byte("device-1"), []byte("t"), []byte("p")), wantErr: true},
+		{name: "will QoS without will", body: connectBody("MQTT", 4, 0x0a, []byte("device-1")), wantErr: true},
+		{name: "will retain without will", body: connectBody("MQTT", 4, 0x22, []byte("device-1")), wantErr: true},
+		{name: "will topic with wildcard", body: connectBody("MQTT", 4, 0x06, []byte("device-1"), []byte("devices/+"), []byte("p")), wantErr: true},
+		{name: "missing password", body: connectBody("MQTT", 4, 0xc2, []byte("device-1"), []byte("alice")), wantErr: true},
+		{name: "truncated variable header", body: []byte{0x00, 0x04, 'M', 'Q'}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cp, code, err := decodeConnect(tt.body)
+
+			if tt.wantErr {
+				if nil == err {
+					t.Fatalf("decodeConnect() = %+v, want error", cp)
+				}
+
+				if code != tt.wantCode {
+					t.Errorf("CONNACK code = %d, want %d", code, tt.wantCode)
+				}
+				return
+			}
+
+			if nil != err {
+				t.Fatalf("decodeConnect(): %v", err)
+			}
+
+			tt.check(t, cp)
+		})
+	}
+}
+
+func TestTopicFilter(t *testing.T) {
+
+	tests := []struct {
+		filter string
+		valid  bool
+	}{
+		{"sensors/temperature", true},
+		{"sensors/+/temperature", true},
+		{"sensors/#", true},
+		{"#", true},
+		{"+", true},
+		{"+/+/#", true},
+		{"/", true},
+		{"sensors/#/temperature", false},
+		{"sensors/temp+", false},
+		{"sensors/temp#", false},
+		{"", false},
+		{"sensors/\x00", false},
+		{"sensors/\xff", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			if valid := validTopicFilter(tt.filter); valid != tt.valid {
+				t.Errorf("validTopicFilter(%q) = %t, want %t", tt.filter, valid, tt.valid)
+			}
+		})
+	}
+}
+
+func TestTopicName(t *testing.T) {
+
+	tests := []struct {
+		topic string
+		valid bool
+	}{
+		{"sensors/temperature", true},
+		{"$SYS/uptime", true},
+		{"sensors/+", false},
+		{"sensors/#", false},
+		{"", false},
+		{"sensors/\x00", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.topic, func(t *testing.T) {
+			if valid := validTopicName(tt.topic); valid != tt.valid {
+				t.Errorf("validTopicName(%q) = %t, want %t", tt.topic, valid, tt.valid)
+			}
+		})
+	}
+}
+
+func TestMatchTopic(t *testing.T) {
+
+	tests := []struct {
+		filter string
+		topic  string
+		match  bool
+	}{
+		{"sensors/temperature", "sensors/temperature", true},
+		{"sensors/temperature", "sensors/humidity", false},
+		{"sensors/+/temperature", "sensors/kitchen/temperature", true},
+		{"sensors/+/temperature", "sensors/kitchen/fridge/temperature", false},
+		{"sensors/+", "sensors", false},
+		{"sensors/#", "sensors", true}, // # also matches the parent level
+		{"sensors/#", "sensors/kitchen/temperature", true},
+		{"#", "sensors/kitchen", true},
+		{"+/+", "/sensors", true}, // leading empty level
+		{"#", "$SYS/uptime", false},
+		{"+/uptime", "$SYS/uptime", false},
+		{"$SYS/#", "$SYS/uptime", true},
+		{"sensors/temperature", "sensors/temperature/max", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter+" "+tt.topic, func(t *testing.T) {
+			if match := matchTopic(tt.filter, tt.topic); match != tt.match {
+				t.Errorf("matchTopic(%q, %q) = %t, want %t", tt.filter, tt.topic, match, tt.match)
+			}
+		})
+	}
+}
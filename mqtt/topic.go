@@ -0,0 +1,94 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package mqtt
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// WildcardSingle - matches exactly one topic level
+	WildcardSingle = "+"
+
+	// WildcardMulti - matches any number of topic levels, must be the last level of a filter
+	WildcardMulti = "#"
+
+	// maxTopicLength - topics are encoded with a two byte length prefix
+	maxTopicLength = 65535
+)
+
+// validTopicName - determines if the given topic can be published to, which excludes wildcards
+func validTopicName(topic string) (valid bool) {
+
+	if len(topic) == 0 || len(topic) > maxTopicLength || !utf8.ValidString(topic) {
+		return false
+	}
+
+	return !strings.ContainsAny(topic, "+#\x00")
+}
+
+// validTopicFilter - determines if the given topic filter is valid, wildcards must occupy an entire level and `#` must be the last level
+func validTopicFilter(filter string) (valid bool) {
+
+	if len(filter) == 0 || len(filter) > maxTopicLength || !utf8.ValidString(filter) || strings.Contains(filter, "\x00") {
+		return false
+	}
+
+	levels := strings.Split(filter, "/")
+	for i, level := range levels {
+		switch {
+		case level == WildcardMulti:
+			if i != len(levels)-1 {
+				return false
+			}
+		case level == WildcardSingle:
+			// single level wildcard is valid anywhere
+		case strings.ContainsAny(level, "+#"):
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchTopic - determines if the given topic name matches the topic filter
+func matchTopic(filter, topic string) (match bool) {
+
+	// topics starting with '$' are reserved and not matched by filters starting with a wildcard
+	if strings.HasPrefix(topic, "$") && (strings.HasPrefix(filter, WildcardSingle) || strings.HasPrefix(filter, WildcardMulti)) {
+		return false
+	}
+
+	filterLevels := strings.Split(filter, "/")
+	topicLevels := strings.Split(topic, "/")
+
+	for i, level := range filterLevels {
+		if level == WildcardMulti {
+			return true // matches the parent level and everything below it
+		}
+
+		if i >= len(topicLevels) {
+			return false
+		}
+
+		if level != WildcardSingle && level != topicLevels[i] {
+			return false
+		}
+	}
+
+	return len(filterLevels) == len(topicLevels)
+}
@@ -16,10 +16,16 @@ limitations under the License.
 package listener
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"strings"
+	"sync"
 
+	"github.com/handletec/listener/mqtt"
 	"github.com/handletec/listener/rest"
+	"github.com/handletec/listener/tcp"
+	"github.com/handletec/listener/udp"
 )
 
 // Protocol - custom protocol definitions
@@ -29,8 +35,70 @@ const (
 	ProtoNone Protocol = iota
 	ProtoREST
 	ProtoMQTT
+	ProtoTCP
+	ProtoUDP
 )
 
+// Factory - creates a new instance of the listener implementing a protocol
+type Factory func() Listener
+
+// protocolEntry - registered protocol name and the factory creating its listener
+type protocolEntry struct {
+	name    string
+	factory Factory
+}
+
+var (
+	// protocols - registered protocols, indexed by their `Protocol` value
+	protocols = []protocolEntry{
+		ProtoNone: {name: "NONE"},
+		ProtoREST: {name: "REST", factory: func() Listener { return rest.New() }},
+		ProtoMQTT: {name: "MQTT", factory: func() Listener { return mqtt.New() }},
+		ProtoTCP:  {name: "TCP", factory: func() Listener { return tcp.New() }},
+		ProtoUDP:  {name: "UDP", factory: func() Listener { return udp.New() }},
+	}
+	protocolsMu sync.RWMutex // protects protocols
+)
+
+// RegisterProtocol - registers a factory for the protocol with the given name and returns its `Protocol` value.
+// A reserved protocol without an implementation can be registered once, registering an implemented protocol again returns an error
+func RegisterProtocol(name string, factory Factory) (proto Protocol, err error) {
+
+	name = strings.ToUpper(strings.TrimSpace(name))
+
+	if len(name) == 0 {
+		return ProtoNone, errors.New("register protocol: name cannot be left blank")
+	}
+
+	if nil == factory {
+		return ProtoNone, fmt.Errorf("register protocol %s: factory cannot be nil", name)
+	}
+
+	protocolsMu.Lock()
+	defer protocolsMu.Unlock()
+
+	for i, p := range protocols {
+		if p.name != name {
+			continue
+		}
+
+		if Protocol(i) == ProtoNone || nil != p.factory {
+			return ProtoNone, fmt.Errorf("register protocol %s: already registered", name)
+		}
+
+		protocols[i].factory = factory
+		return Protocol(i), nil
+	}
+
+	if len(protocols) > math.MaxUint8 {
+		return ProtoNone, fmt.Errorf("register protocol %s: too many protocols registered", name)
+	}
+
+	protocols = append(protocols, protocolEntry{name: name, factory: factory})
+
+	return Protocol(len(protocols) - 1), nil
+}
+
 // Listener - returns listener implementation for this protocol
 func (proto Protocol) Listener() (l Listener, err error) {
 
@@ -38,11 +106,17 @@ func (proto Protocol) Listener() (l Listener, err error) {
 		return nil, fmt.Errorf("proto listener: unknown protocol given, cannot create listener")
 	}
 
-	switch proto {
-	case ProtoREST:
-		l = new(rest.Listener)
-	case ProtoMQTT:
-		//l = new(mqtt.Listener)
+	protocolsMu.RLock()
+	factory := protocols[proto].factory
+	protocolsMu.RUnlock()
+
+	if nil == factory {
+		return nil, fmt.Errorf("proto listener: protocol %s is not implemented", proto)
+	}
+
+	l = factory()
+	if nil == l {
+		return nil, fmt.Errorf("proto listener: protocol %s factory returned no listener", proto)
 	}
 
 	return
@@ -59,28 +133,32 @@ func (proto Protocol) IsValid() (valid bool) {
 
 func (proto Protocol) String() (str string) {
 
-	protoName := []string{"NONE", "REST", "MQTT"}
+	protocolsMu.RLock()
+	defer protocolsMu.RUnlock()
+
 	protoInt := int(proto)
 
-	if protoInt < 0 || protoInt >= len(protoName) {
+	if protoInt < 0 || protoInt >= len(protocols) {
 		protoInt = 0
 	}
 
-	return protoName[protoInt]
+	return protocols[protoInt].name
 }
 
 // ParseProto - returns protocol type from given string
 func ParseProto(protoStr string) (proto Protocol) {
 
-	switch strings.ToUpper(protoStr) {
-	case "REST":
-		proto = ProtoREST
-	case "MQTT":
-		proto = ProtoMQTT
-	default:
-		// if something unrecognized is given, set it to none
-		proto = ProtoNone
+	protoStr = strings.ToUpper(strings.TrimSpace(protoStr))
+
+	protocolsMu.RLock()
+	defer protocolsMu.RUnlock()
+
+	for i, p := range protocols {
+		if p.name == protoStr {
+			return Protocol(i)
+		}
 	}
 
-	return
+	// if something unrecognized is given, set it to none
+	return ProtoNone
 }
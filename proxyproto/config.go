@@ -0,0 +1,88 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package proxyproto
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"time"
+
+	"github.com/handletec/listener/ipfilter"
+)
+
+// DefaultHeaderTimeout - time a trusted source has to send the PROXY header once the connection is first used
+const DefaultHeaderTimeout = 5 * time.Second
+
+// Config - PROXY protocol configuration, for listeners behind load balancers such as HAProxy or AWS NLB sending the address of the original client
+type Config struct {
+	Trusted       []netip.Prefix // sources allowed to send a PROXY header, connections from any other source are used as they are
+	HeaderTimeout time.Duration  // maximum time a trusted source has to send the header, 0 waits forever
+	Optional      bool           // trusted sources may connect without a header, keeping their own address
+}
+
+// NewConfig - creates new instance of config trusting the given CIDRs or IP addresses, e.g. `10.0.0.0/8` or `192.168.1.10`
+func NewConfig(trusted ...string) (cfg *Config, err error) {
+	cfg = new(Config)
+
+	// set default configuration
+	cfg.HeaderTimeout = DefaultHeaderTimeout
+
+	for _, t := range trusted {
+		if err = cfg.Trust(t); nil != err {
+			return nil, err
+		}
+	}
+
+	return
+}
+
+// Trust - allows the CIDR or IP address to send a PROXY header
+func (cfg *Config) Trust(cidr string) (err error) {
+
+	prefix, err := ipfilter.ParsePrefix(cidr)
+	if nil != err {
+		return fmt.Errorf("proxy protocol trust: %w", err)
+	}
+
+	cfg.Trusted = append(cfg.Trusted, prefix)
+
+	return
+}
+
+// trusts - determines if the connection from addr is allowed to send a PROXY header
+func (cfg *Config) trusts(addr net.Addr) (trusted bool) {
+
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+
+	ip, ok := netip.AddrFromSlice(tcpAddr.IP)
+	if !ok {
+		return false
+	}
+
+	ip = ip.Unmap() // IPv4 clients of a dual stack socket are reported as IPv4 mapped IPv6 addresses
+
+	for _, prefix := range cfg.Trusted {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,196 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package proxyproto
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrMissingHeader - returned when a trusted source does not start the connection with a PROXY header
+	ErrMissingHeader = errors.New("proxy protocol: missing header")
+
+	// ErrInvalidHeader - returned when the PROXY header is malformed or incomplete
+	ErrInvalidHeader = errors.New("proxy protocol: invalid header")
+)
+
+const (
+	v1Prefix    = "PROXY "
+	v1MaxLength = 107 // longest possible v1 header, including the CRLF
+	v2Length    = 16  // fixed part of a v2 header, followed by the addresses
+)
+
+// v2Signature - first 12 bytes of every v2 header
+var v2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+
+// readHeader - reads a v1 or v2 header. The addresses are nil when the header does not carry them, e.g. health checks sent by the load balancer itself
+func readHeader(r *bufio.Reader) (src, dst net.Addr, err error) {
+
+	first, err := r.Peek(1)
+	if nil != err {
+		var ne net.Error
+		if errors.As(err, &ne) && ne.Timeout() {
+			return nil, nil, ErrMissingHeader // nothing was sent, e.g. clients waiting for the server to speak first
+		}
+
+		return nil, nil, invalid(err)
+	}
+
+	switch first[0] {
+	case v1Prefix[0]:
+		if peek, e := r.Peek(len(v1Prefix)); nil == e && string(peek) == v1Prefix {
+			return readV1(r)
+		}
+	case v2Signature[0]:
+		if peek, e := r.Peek(len(v2Signature)); nil == e && bytes.Equal(peek, v2Signature) {
+			return readV2(r)
+		}
+	}
+
+	// nothing was consumed, so the connection can still be used as is
+	return nil, nil, ErrMissingHeader
+}
+
+// readV1 - reads the human readable header, e.g. `PROXY TCP4 203.0.113.7 10.0.0.5 51234 443\r\n`
+func readV1(r *bufio.Reader) (src, dst net.Addr, err error) {
+
+	line, err := r.ReadSlice('\n')
+	if nil != err {
+		return nil, nil, invalid(err)
+	}
+
+	if len(line) > v1MaxLength || !bytes.HasSuffix(line, []byte("\r\n")) {
+		return nil, nil, fmt.Errorf("%w: malformed v1 header", ErrInvalidHeader)
+	}
+
+	fields := strings.Split(string(line[len(v1Prefix):len(line)-2]), " ")
+
+	switch fields[0] {
+	case "UNKNOWN":
+		return nil, nil, nil // the rest of the line is ignored
+	case "TCP4", "TCP6":
+	default:
+		return nil, nil, fmt.Errorf("%w: unknown v1 protocol '%s'", ErrInvalidHeader, fields[0])
+	}
+
+	if len(fields) != 5 {
+		return nil, nil, fmt.Errorf("%w: malformed v1 header", ErrInvalidHeader)
+	}
+
+	v4 := fields[0] == "TCP4"
+
+	srcAddr, err := v1Addr(fields[1], fields[3], v4)
+	if nil != err {
+		return nil, nil, err
+	}
+
+	dstAddr, err := v1Addr(fields[2], fields[4], v4)
+	if nil != err {
+		return nil, nil, err
+	}
+
+	return srcAddr, dstAddr, nil
+}
+
+// v1Addr - parses an address and port of a v1 header
+func v1Addr(ipStr, portStr string, v4 bool) (addr *net.TCPAddr, err error) {
+
+	ip := net.ParseIP(ipStr)
+	if nil == ip || (nil != ip.To4()) != v4 {
+		return nil, fmt.Errorf("%w: invalid v1 address '%s'", ErrInvalidHeader, ipStr)
+	}
+
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if nil != err {
+		return nil, fmt.Errorf("%w: invalid v1 port '%s'", ErrInvalidHeader, portStr)
+	}
+
+	return &net.TCPAddr{IP: ip, Port: int(port)}, nil
+}
+
+// readV2 - reads the binary header
+func readV2(r *bufio.Reader) (src, dst net.Addr, err error) {
+
+	header := make([]byte, v2Length)
+	if _, err = io.ReadFull(r, header); nil != err {
+		return nil, nil, invalid(err)
+	}
+
+	version, command := header[12]>>4, header[12]&0x0F
+	family := header[13] >> 4
+
+	if version != 2 {
+		return nil, nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidHeader, version)
+	}
+
+	payload := make([]byte, binary.BigEndian.Uint16(header[14:16]))
+	if _, err = io.ReadFull(r, payload); nil != err {
+		return nil, nil, invalid(err)
+	}
+
+	switch command {
+	case 0x0:
+		return nil, nil, nil // LOCAL, sent by the load balancer itself such as health checks
+	case 0x1:
+		// PROXY
+	default:
+		return nil, nil, fmt.Errorf("%w: unknown v2 command %d", ErrInvalidHeader, command)
+	}
+
+	var size int
+	switch family {
+	case 0x1: // AF_INET
+		size = net.IPv4len
+	case 0x2: // AF_INET6
+		size = net.IPv6len
+	default:
+		return nil, nil, nil // AF_UNSPEC and AF_UNIX carry no IP address
+	}
+
+	if len(payload) < 2*size+4 {
+		return nil, nil, fmt.Errorf("%w: v2 addresses truncated", ErrInvalidHeader)
+	}
+
+	// type-length-values following the addresses are ignored
+	src = &net.TCPAddr{
+		IP:   net.IP(append([]byte(nil), payload[:size]...)),
+		Port: int(binary.BigEndian.Uint16(payload[2*size:])),
+	}
+
+	dst = &net.TCPAddr{
+		IP:   net.IP(append([]byte(nil), payload[size:2*size]...)),
+		Port: int(binary.BigEndian.Uint16(payload[2*size+2:])),
+	}
+
+	return
+}
+
+// invalid - a connection closing or timing out in the middle of a header is an invalid header
+func invalid(err error) error {
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+
+	return fmt.Errorf("%w: %w", ErrInvalidHeader, err)
+}
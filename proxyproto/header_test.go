@@ -0,0 +1,155 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package proxyproto
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+)
+
+// v2Header - builds a v2 header with the given version and command, address family and addresses
+func v2Header(versionCommand, family byte, addrs []byte) (header string) {
+
+	b := append([]byte(nil), v2Signature...)
+	b = append(b, versionCommand, family<<4|0x1)
+	b = binary.BigEndian.AppendUint16(b, uint16(len(addrs)))
+	b = append(b, addrs...)
+
+	return string(b)
+}
+
+// v2Addrs - encodes the source and destination addresses of a v2 header
+func v2Addrs(src, dst string, srcPort, dstPort uint16) (addrs []byte) {
+
+	srcIP, dstIP := net.ParseIP(src), net.ParseIP(dst)
+	if v4 := srcIP.To4(); nil != v4 {
+		srcIP, dstIP = v4, dstIP.To4()
+	}
+
+	addrs = append(addrs, srcIP...)
+	addrs = append(addrs, dstIP...)
+	addrs = binary.BigEndian.AppendUint16(addrs, srcPort)
+	addrs = binary.BigEndian.AppendUint16(addrs, dstPort)
+
+	return
+}
+
+func TestReadHeader(t *testing.T) {
+
+	v4Addrs := v2Addrs("203.0.113.7", "10.0.0.5", 51234, 443)
+	v6Addrs := v2Addrs("2001:db8::7", "2001:db8::5", 51234, 443)
+
+	tests := []struct {
+		name    string
+		input   string
+		src     string // empty when the header carries no addresses
+		dst     string
+		wantErr error
+	}{
+		// v1
+		{name: "v1 TCP4", input: "PROXY TCP4 203.0.113.7 10.0.0.5 51234 443\r\n", src: "203.0.113.7:51234", dst: "10.0.0.5:443"},
+		{name: "v1 TCP6", input: "PROXY TCP6 2001:db8::7 2001:db8::5 51234 443\r\n", src: "[2001:db8::7]:51234", dst: "[2001:db8::5]:443"},
+		{name: "v1 UNKNOWN", input: "PROXY UNKNOWN ignored until the end\r\n"},
+		{name: "v1 unknown protocol", input: "PROXY UDP4 203.0.113.7 10.0.0.5 51234 443\r\n", wantErr: ErrInvalidHeader},
+		{name: "v1 missing port", input: "PROXY TCP4 203.0.113.7 10.0.0.5 51234\r\n", wantErr: ErrInvalidHeader},
+		{name: "v1 IPv6 address with TCP4", input: "PROXY TCP4 2001:db8::7 10.0.0.5 51234 443\r\n", wantErr: ErrInvalidHeader},
+		{name: "v1 IPv4 address with TCP6", input: "PROXY TCP6 203.0.113.7 2001:db8::5 51234 443\r\n", wantErr: ErrInvalidHeader},
+		{name: "v1 invalid address", input: "PROXY TCP4 203.0.113 10.0.0.5 51234 443\r\n", wantErr: ErrInvalidHeader},
+		{name: "v1 port out of range", input: "PROXY TCP4 203.0.113.7 10.0.0.5 65536 443\r\n", wantErr: ErrInvalidHeader},
+		{name: "v1 without CR", input: "PROXY TCP4 203.0.113.7 10.0.0.5 51234 443\n", wantErr: ErrInvalidHeader},
+		{name: "v1 too long", input: "PROXY UNKNOWN " + strings.Repeat("a", v1MaxLength) + "\r\n", wantErr: ErrInvalidHeader},
+		{name: "v1 truncated", input: "PROXY TCP4 203.0.113.7", wantErr: io.ErrUnexpectedEOF},
+
+		// v2
+		{name: "v2 IPv4", input: v2Header(0x21, 0x1, v4Addrs), src: "203.0.113.7:51234", dst: "10.0.0.5:443"},
+		{name: "v2 IPv6", input: v2Header(0x21, 0x2, v6Addrs), src: "[2001:db8::7]:51234", dst: "[2001:db8::5]:443"},
+		{name: "v2 trailing TLVs ignored", input: v2Header(0x21, 0x1, append(append([]byte(nil), v4Addrs...), 0x04, 0x00, 0x01, 0xff)), src: "203.0.113.7:51234", dst: "10.0.0.5:443"},
+		{name: "v2 LOCAL", input: v2Header(0x20, 0x1, v4Addrs)},
+		{name: "v2 AF_UNSPEC", input: v2Header(0x21, 0x0, nil)},
+		{name: "v2 unsupported version", input: v2Header(0x11, 0x1, v4Addrs), wantErr: ErrInvalidHeader},
+		{name: "v2 unknown command", input: v2Header(0x22, 0x1, v4Addrs), wantErr: ErrInvalidHeader},
+		{name: "v2 addresses shorter than the family", input: v2Header(0x21, 0x2, v4Addrs), wantErr: ErrInvalidHeader},
+		{name: "v2 truncated header", input: v2Header(0x21, 0x1, v4Addrs)[:14], wantErr: io.ErrUnexpectedEOF},
+		{name: "v2 truncated addresses", input: v2Header(0x21, 0x1, v4Addrs)[:20], wantErr: io.ErrUnexpectedEOF},
+
+		// no header
+		{name: "plain request", input: "GET / HTTP/1.1\r\n", wantErr: ErrMissingHeader},
+		{name: "prefix of v1 signature only", input: "PROXYTCP4\r\n", wantErr: ErrMissingHeader},
+		{name: "prefix of v2 signature only", input: "\r\n\r\nGET", wantErr: ErrMissingHeader},
+		{name: "empty connection", input: "", wantErr: io.ErrUnexpectedEOF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const payload = "payload"
+
+			r := bufio.NewReader(strings.NewReader(tt.input + payload))
+			if nil != tt.wantErr {
+				r = bufio.NewReader(strings.NewReader(tt.input))
+			}
+
+			src, dst, err := readHeader(r)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("readHeader() = %v, expected %v", err, tt.wantErr)
+			}
+
+			if nil != tt.wantErr {
+				return
+			}
+
+			if got := addrString(src); got != tt.src {
+				t.Errorf("source %s, expected %s", got, tt.src)
+			}
+
+			if got := addrString(dst); got != tt.dst {
+				t.Errorf("destination %s, expected %s", got, tt.dst)
+			}
+
+			// the header must be consumed entirely, leaving the data of the client
+			if rest, _ := io.ReadAll(r); string(rest) != payload {
+				t.Errorf("remaining data %q, expected %q", rest, payload)
+			}
+		})
+	}
+}
+
+func TestReadHeaderMissingLeavesData(t *testing.T) {
+
+	const input = "GET / HTTP/1.1\r\n"
+
+	r := bufio.NewReader(strings.NewReader(input))
+	if _, _, err := readHeader(r); !errors.Is(err, ErrMissingHeader) {
+		t.Fatalf("readHeader() = %v, expected %v", err, ErrMissingHeader)
+	}
+
+	if rest, _ := io.ReadAll(r); string(rest) != input {
+		t.Fatalf("remaining data %q, expected %q", rest, input)
+	}
+}
+
+// addrString - returns the address as a string, empty when nil
+func addrString(addr net.Addr) (s string) {
+	if nil == addr {
+		return ""
+	}
+
+	return addr.String()
+}
@@ -0,0 +1,163 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package proxyproto
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+// readerSize - buffer size of the header reader, large enough for the longest v1 header
+const readerSize = 256
+
+// Listener - wraps a listener to read the PROXY header of connections from trusted sources.
+// Wrap the listener before TLS, the load balancer sends the header ahead of the TLS handshake
+type Listener struct {
+	net.Listener
+	config *Config
+}
+
+// NewListener - create new instance of a listener reading PROXY headers from connections accepted by ln
+func NewListener(ln net.Listener, config *Config) (pl *Listener) {
+	pl = new(Listener)
+	pl.Listener = ln
+	pl.config = config
+
+	return
+}
+
+// Accept - waits for the next connection. The header is read the first time the connection is used rather than here, so a slow client never holds up other connections
+func (pl *Listener) Accept() (conn net.Conn, err error) {
+
+	conn, err = pl.Listener.Accept()
+	if nil != err {
+		return nil, err
+	}
+
+	if !pl.config.trusts(conn.RemoteAddr()) {
+		return conn, nil // untrusted sources cannot claim another address, their connection is used as it is
+	}
+
+	return newConn(conn, pl.config), nil
+}
+
+// Conn - connection from a trusted source, reporting the addresses carried by its PROXY header
+type Conn struct {
+	net.Conn
+	r        *bufio.Reader
+	config   *Config
+	once     sync.Once
+	src      net.Addr   // original client, nil if the header did not carry it
+	dst      net.Addr   // address the client connected to on the load balancer
+	err      error      // error reading the header, returned by every read
+	deadline time.Time  // read deadline set by the caller, restored once the header is read
+	mu       sync.Mutex // protects deadline
+}
+
+// newConn - create new instance of a connection from a trusted source
+func newConn(conn net.Conn, config *Config) (c *Conn) {
+	c = new(Conn)
+	c.Conn = conn
+	c.r = bufio.NewReaderSize(conn, readerSize)
+	c.config = config
+
+	return
+}
+
+// Read - reads data following the header, or returns the error reading the header
+func (c *Conn) Read(b []byte) (n int, err error) {
+
+	if err = c.Header(); nil != err {
+		return 0, err
+	}
+
+	if c.r.Buffered() > 0 {
+		return c.r.Read(b)
+	}
+
+	return c.Conn.Read(b)
+}
+
+// RemoteAddr - returns the address of the original client, or of the load balancer if the header did not carry one.
+// It blocks until the header is read, for at most the header timeout, so call it from the goroutine serving the connection rather than from the
+// loop accepting connections. `http.Server` calls it once it starts serving the connection, which is how `r.RemoteAddr` holds the original client
+func (c *Conn) RemoteAddr() (addr net.Addr) {
+	c.Header()
+
+	if nil != c.src {
+		return c.src
+	}
+
+	return c.Conn.RemoteAddr()
+}
+
+// LocalAddr - returns the address the client connected to on the load balancer, or the local address if the header did not carry one.
+// Like `RemoteAddr`, it blocks until the header is read
+func (c *Conn) LocalAddr() (addr net.Addr) {
+	c.Header()
+
+	if nil != c.dst {
+		return c.dst
+	}
+
+	return c.Conn.LocalAddr()
+}
+
+// SetDeadline - sets the read and write deadlines
+func (c *Conn) SetDeadline(t time.Time) (err error) {
+	c.mu.Lock()
+	c.deadline = t
+	c.mu.Unlock()
+
+	return c.Conn.SetDeadline(t)
+}
+
+// SetReadDeadline - sets the read deadline
+func (c *Conn) SetReadDeadline(t time.Time) (err error) {
+	c.mu.Lock()
+	c.deadline = t
+	c.mu.Unlock()
+
+	return c.Conn.SetReadDeadline(t)
+}
+
+// Header - reads the PROXY header if it was not read yet, returning the error if it is missing or invalid
+func (c *Conn) Header() (err error) {
+	c.once.Do(c.readHeader)
+	return c.err
+}
+
+// readHeader - reads the header within the header timeout
+func (c *Conn) readHeader() {
+
+	if c.config.HeaderTimeout > 0 {
+		c.Conn.SetReadDeadline(time.Now().Add(c.config.HeaderTimeout))
+	}
+
+	c.src, c.dst, c.err = readHeader(c.r)
+	if errors.Is(c.err, ErrMissingHeader) && c.config.Optional {
+		c.err = nil
+	}
+
+	c.mu.Lock()
+	deadline := c.deadline
+	c.mu.Unlock()
+
+	c.Conn.SetReadDeadline(deadline)
+}
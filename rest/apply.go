@@ -0,0 +1,163 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package rest
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ApplyConfig - validates the configuration and custom headers and swaps them into the listener without restarting it, replacing the CORS,
+// RPS, timeouts, compression, authentication, trusted proxies, custom headers and router. Requests already being served complete with the previous configuration.
+// Socket permissions, listen functions, IP filters, connection limits and the PROXY protocol only apply the next time the listener is started. An invalid configuration is rejected and the current one is kept
+func (l *Listener) ApplyConfig(config *Config, header *Header) (err error) {
+
+	l.applyMu.Lock()
+	defer l.applyMu.Unlock()
+
+	logger := l.logger
+	if nil == logger {
+		logger = slog.Default() // listener is not initialized yet
+	}
+
+	if err = l.applyConfig(config, header); nil != err {
+		logger.Warn("config rejected", "listener", l.Name(), "error", err)
+		return fmt.Errorf("REST apply config: %w", err)
+	}
+
+	l.mu.Lock()
+	previous, previousHeader := l.config, l.header
+	l.config = config
+	l.header = header
+	running := nil != l.server
+	l.mu.Unlock()
+
+	logger.Info("config applied", "listener", l.Name(), "running", running, "changes", configChanges(previous, previousHeader, config, header))
+
+	return
+}
+
+// applyConfig - validates the configuration and, once the listener was started, builds and swaps in its middleware stack
+func (l *Listener) applyConfig(config *Config, header *Header) (err error) {
+
+	if err = config.validate(); nil != err {
+		return
+	}
+
+	if nil == l.mux.Load() {
+		return // never started, the middleware stack is built by `Start`
+	}
+
+	var mux *chi.Mux
+	if mux, err = l.newMux(config, header); nil != err {
+		return
+	}
+
+	l.mux.Store(mux)
+
+	return
+}
+
+// configChanges - describes every setting that differs between two configurations, for the audit log
+func configChanges(previous *Config, previousHeader *Header, config *Config, header *Header) (changes []string) {
+
+	changed := func(name string, from, to any) {
+		if !reflect.DeepEqual(from, to) {
+			changes = append(changes, fmt.Sprintf("%s: %v -> %v", name, from, to))
+		}
+	}
+
+	replaced := func(name string, from, to any) {
+		if !sameValue(from, to) {
+			changes = append(changes, name+": replaced")
+		}
+	}
+
+	if nil == previous {
+		previous = new(Config)
+	}
+
+	previousCORS := previous.CORS
+	if nil == previousCORS {
+		previousCORS = new(CORS)
+	}
+
+	changed("rps", previous.RPS, config.RPS)
+	changed("timeout", previous.Timeout, config.Timeout)
+	changed("shutdown_timeout", previous.ShutdownTimeout, config.ShutdownTimeout)
+	changed("compress", previous.compress, config.compress)
+	changed("cors_origins", previousCORS.AllowedOrigins, config.CORS.AllowedOrigins)
+	changed("cors_methods", previousCORS.AllowedMethods, config.CORS.AllowedMethods)
+	changed("cors_headers", previousCORS.AllowedHeaders, config.CORS.AllowedHeaders)
+	changed("cors_max_age", previousCORS.MaxAge, config.CORS.MaxAge)
+	changed("cors_allow_credentials", previousCORS.AllowCredentials, config.CORS.AllowCredentials)
+	changed("cors_debug", previousCORS.Debug, config.CORS.Debug)
+	changed("headers", headerMap(previousHeader), headerMap(header))
+	changed("socket_mode", previous.SocketMode, config.SocketMode)
+	changed("socket_uid", previous.SocketUID, config.SocketUID)
+	changed("socket_gid", previous.SocketGID, config.SocketGID)
+	changed("trusted_proxies", previous.trustedProxies, config.trustedProxies)
+	replaced("ip_filter", previous.IPFilter, config.IPFilter)
+	replaced("listen", previous.Listen, config.Listen)
+	if !reflect.DeepEqual(previous.ConnLimit, config.ConnLimit) {
+		changes = append(changes, "conn_limit: replaced")
+	}
+	if !reflect.DeepEqual(previous.ProxyProtocol, config.ProxyProtocol) {
+		changes = append(changes, "proxy_protocol: replaced")
+	}
+	replaced("router", previous.router, config.router)
+	replaced("authenticator", previous.authn, config.authn)
+	replaced("authorizer", previous.authz, config.authz)
+
+	return
+}
+
+// headerMap - returns the custom headers, nil if there are none
+func headerMap(header *Header) (m map[string]string) {
+	if nil == header || len(*header) == 0 {
+		return nil
+	}
+
+	return *header
+}
+
+// sameValue - determines if both values are the same instance, functions and pointers are compared by address as they cannot be compared otherwise
+func sameValue(a, b any) (same bool) {
+
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+
+	if !va.IsValid() || !vb.IsValid() {
+		return va.IsValid() == vb.IsValid()
+	}
+
+	if va.Type() != vb.Type() {
+		return false
+	}
+
+	switch va.Kind() {
+	case reflect.Func, reflect.Pointer, reflect.Map, reflect.Chan, reflect.Slice, reflect.UnsafePointer:
+		return va.Pointer() == vb.Pointer()
+	}
+
+	if !va.Comparable() {
+		return false
+	}
+
+	return a == b
+}
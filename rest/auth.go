@@ -0,0 +1,143 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package rest
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/netip"
+
+	"github.com/handletec/listener/auth"
+)
+
+// Authenticate - middleware authenticating every request and storing the principal in the request context, unauthenticated requests are rejected with 401.
+// Requests already carrying a principal, authenticated by a middleware further up, are not authenticated again. OPTIONS requests are never authenticated
+func Authenticate(authn auth.Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			if r.Method == http.MethodOptions {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if _, ok := auth.FromContext(r.Context()); ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, _, err := auth.Authenticate(r.Context(), authn, newAuthRequest(r))
+			if nil != err {
+				authError(w, err)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Authorize - middleware authorizing the principal in the request context, using the HTTP method as the action and the URL path as the resource.
+// Requests without a principal are rejected with 401, requests that are not allowed with 403. OPTIONS requests are never authorized
+func Authorize(authz auth.Authorizer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			if r.Method == http.MethodOptions {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if err := auth.Authorize(r.Context(), authz, r.Method, r.URL.Path); nil != err {
+				authError(w, err)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Auth - middleware authenticating then authorizing every request, either of them may be nil to skip that step.
+// Pass it to `NewRouter`, `NewGroup` or `Set` to protect a router, group or single route
+func Auth(authn auth.Authenticator, authz auth.Authorizer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if nil != authz {
+			next = Authorize(authz)(next)
+		}
+
+		if nil != authn {
+			next = Authenticate(authn)(next)
+		}
+
+		return next
+	}
+}
+
+// newAuthRequest - describes the HTTP request for authenticators
+func newAuthRequest(r *http.Request) (req *auth.Request) {
+	req = new(auth.Request)
+	req.Protocol = "REST"
+	req.TLS = r.TLS
+	req.Metadata = auth.Metadata(r.Header)
+	req.Raw = r
+	req.RemoteAddr = remoteAddr(r)
+
+	return
+}
+
+// peerKey - context key of the connection a request was received on
+type peerKey struct{}
+
+// withPeer - stores the connection in the context of every request received on it, set as `http.Server.ConnContext`.
+// It runs in the accept loop of the server, so the remote address is only read once a request is handled: a PROXY protocol connection
+// waiting for its header must never hold up accepting other clients
+func withPeer(ctx context.Context, conn net.Conn) context.Context {
+	return context.WithValue(ctx, peerKey{}, conn)
+}
+
+// remoteAddr - returns the address of the client, as reported by `r.RemoteAddr` once the PROXY protocol and trusted proxies were applied.
+// Trusted proxies report the IP address of the client without a port. Connections without an IP address, such as Unix sockets, report
+// the remote address of the connection itself
+func remoteAddr(r *http.Request) (addr net.Addr) {
+
+	if addrPort, err := netip.ParseAddrPort(r.RemoteAddr); nil == err {
+		return net.TCPAddrFromAddrPort(netip.AddrPortFrom(addrPort.Addr().Unmap(), addrPort.Port()))
+	}
+
+	if ip, err := netip.ParseAddr(r.RemoteAddr); nil == err {
+		return net.TCPAddrFromAddrPort(netip.AddrPortFrom(ip.Unmap(), 0))
+	}
+
+	if conn, ok := r.Context().Value(peerKey{}).(net.Conn); ok {
+		return conn.RemoteAddr()
+	}
+
+	return nil
+}
+
+// authError - rejects the request with the status matching the error
+func authError(w http.ResponseWriter, err error) {
+
+	if errors.Is(err, auth.ErrForbidden) {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+}
@@ -0,0 +1,96 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package rest
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/handletec/listener/auth"
+	"github.com/handletec/listener/memnet"
+)
+
+func TestRemoteAddr(t *testing.T) {
+
+	unix, _ := memnet.Pipe(memnet.Addr("/run/app.sock"), &net.UnixAddr{Name: "@", Net: "unix"})
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		peer       net.Conn
+		want       string // empty when no address is expected
+	}{
+		{name: "IPv4 with port", remoteAddr: "192.0.2.1:5000", want: "192.0.2.1:5000"},
+		{name: "IPv6 with port", remoteAddr: "[2001:db8::1]:443", want: "[2001:db8::1]:443"},
+		{name: "IPv4 mapped IPv6", remoteAddr: "[::ffff:192.0.2.1]:5000", want: "192.0.2.1:5000"},
+		{name: "bare IPv4 from a trusted proxy", remoteAddr: "198.51.100.1", want: "198.51.100.1:0"},
+		{name: "bare IPv6 from a trusted proxy", remoteAddr: "2001:db8::2", want: "[2001:db8::2]:0"},
+		{name: "unix socket falls back to the connection", remoteAddr: "@", peer: unix, want: "@"},
+		{name: "no address", remoteAddr: "garbage"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if nil != tt.peer {
+				req = req.WithContext(context.WithValue(req.Context(), peerKey{}, tt.peer))
+			}
+
+			got := remoteAddr(req)
+
+			switch {
+			case len(tt.want) == 0 && nil != got:
+				t.Errorf("remoteAddr() = %s, want nil", got)
+			case len(tt.want) > 0 && nil == got:
+				t.Errorf("remoteAddr() = nil, want %s", tt.want)
+			case nil != got && got.String() != tt.want:
+				t.Errorf("remoteAddr() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthenticateRemoteAddr(t *testing.T) {
+
+	var got net.Addr
+	authn := auth.AuthenticatorFunc(func(ctx context.Context, req *auth.Request) (p *auth.Principal, err error) {
+		got = req.RemoteAddr
+		return &auth.Principal{Subject: "alice"}, nil
+	})
+
+	// addresses forwarded by trusted proxies have no port, they must still reach the authenticator
+	handler := realIPMiddleware([]netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")})(Authenticate(authn)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.2:5000"
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	if nil == got || got.String() != "198.51.100.1:0" {
+		t.Errorf("authenticator received remote address %v, want 198.51.100.1:0", got)
+	}
+}
@@ -16,15 +16,37 @@ limitations under the License.
 package rest
 
 import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"os"
 	"time"
+
+	"github.com/handletec/listener/auth"
+	"github.com/handletec/listener/connlimit"
+	"github.com/handletec/listener/internal/accept"
+	"github.com/handletec/listener/ipfilter"
+	"github.com/handletec/listener/proxyproto"
+	"github.com/handletec/listener/socket"
 )
 
 // Config - listener specific configuration
 type Config struct {
-	CORS     *CORS
-	RPS      int
-	Timeout  time.Duration
-	compress bool // compress response to requester
+	CORS            *CORS
+	RPS             int
+	Timeout         time.Duration
+	ShutdownTimeout time.Duration      // maximum time to wait for in-flight requests to complete when the listener is stopped
+	SocketMode      os.FileMode        // permissions of the socket file when listening on a Unix socket
+	SocketUID       int                // owner of the socket file when listening on a Unix socket, -1 leaves it unchanged
+	SocketGID       int                // group of the socket file when listening on a Unix socket, -1 leaves it unchanged
+	IPFilter        *ipfilter.Filter   // (optional) allow and deny lists of client addresses, checked as soon as connections are accepted, so against the load balancer with the PROXY protocol
+	ConnLimit       *connlimit.Config  // (optional) caps the open connections, in total and from each client IP address
+	ProxyProtocol   *proxyproto.Config // (optional) read the address of the original client from the PROXY header sent by trusted load balancers
+	Listen          socket.ListenFunc  // (optional) binds every endpoint instead of `net.Listen`, e.g. an in-memory listener for tests
+	compress        bool               // compress response to requester
+	authn           auth.Authenticator
+	authz           auth.Authorizer
+	trustedProxies  []netip.Prefix // reverse proxies whose `X-Forwarded-For` and `X-Real-IP` headers replace the address of requests
 	//handlers http.Handler
 	router *Router
 }
@@ -36,6 +58,10 @@ func NewConfig() (cfg *Config) {
 	// set default configuration
 	cfg.RPS = 4096 // default request per second
 	cfg.Timeout = time.Duration(15 * time.Second)
+	cfg.ShutdownTimeout = time.Duration(30 * time.Second)
+	cfg.SocketMode = 0660 // read and write for the owner and group only
+	cfg.SocketUID = -1
+	cfg.SocketGID = -1
 	cfg.CORS = NewCORS()
 	cfg.router = nil // default create a nil instance of handler for error checking
 
@@ -53,7 +79,65 @@ func (cfg *Config) SetRouter(router *Router) (err error) {
 	return
 }
 
+// SetAuth - sets the authenticator and authorizer run for every request of the listener, either of them may be nil to skip that step.
+// Use the `Auth` middleware to protect a single router, group or route instead
+func (cfg *Config) SetAuth(authn auth.Authenticator, authz auth.Authorizer) {
+	cfg.authn = authn
+	cfg.authz = authz
+}
+
+// SetTrustedProxies - trusts the `X-Forwarded-For` and `X-Real-IP` headers of requests sent by the reverse proxies at the given CIDRs or
+// IP addresses, e.g. `10.0.0.0/8` or `192.168.1.10`, so the address of the client they forwarded is reported by `r.RemoteAddr`.
+// Headers sent by any other peer are ignored, and they are never trusted when none are set. Cannot be used with the PROXY protocol
+func (cfg *Config) SetTrustedProxies(cidrs ...string) (err error) {
+
+	var prefixes []netip.Prefix
+
+	for _, cidr := range cidrs {
+		prefix, err := ipfilter.ParsePrefix(cidr)
+		if nil != err {
+			return fmt.Errorf("trusted proxy: %w", err)
+		}
+
+		prefixes = append(prefixes, prefix)
+	}
+
+	cfg.trustedProxies = prefixes
+
+	return
+}
+
 // EnableCompress - enable or disable gzip compression
 func (cfg *Config) EnableCompress(compress bool) {
 	cfg.compress = compress
 }
+
+// validate - checks the configuration can be used to serve requests
+func (cfg *Config) validate() (err error) {
+
+	switch {
+	case nil == cfg:
+		return errors.New("no configuration set")
+	case nil == cfg.router:
+		return errors.New("no HTTP routers configured")
+	case nil == cfg.CORS:
+		return errors.New("no CORS configuration set")
+	case cfg.RPS <= 0:
+		return fmt.Errorf("invalid RPS %d, must be greater than 0", cfg.RPS)
+	case cfg.Timeout <= 0:
+		return fmt.Errorf("invalid timeout %s, must be greater than 0", cfg.Timeout)
+	case cfg.ShutdownTimeout < 0:
+		return fmt.Errorf("invalid shutdown timeout %s", cfg.ShutdownTimeout)
+	case cfg.CORS.MaxAge < 0:
+		return fmt.Errorf("invalid CORS max age %d", cfg.CORS.MaxAge)
+	case len(cfg.trustedProxies) > 0 && nil != cfg.ProxyProtocol:
+		return errors.New("trusted proxies cannot be used with the PROXY protocol, which already reports the address of the client")
+	}
+
+	chain := accept.Chain{IPFilter: cfg.IPFilter, ConnLimit: cfg.ConnLimit, ProxyProtocol: cfg.ProxyProtocol}
+	if err = chain.Validate(); nil != err {
+		return err
+	}
+
+	return
+}
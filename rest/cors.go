@@ -15,6 +15,11 @@ limitations under the License.
 */
 package rest
 
+import (
+	"context"
+	"net/http"
+)
+
 // CORS - Cross Origin Resource Sharing configuration
 type CORS struct {
 	AllowedOrigins   []string
@@ -61,3 +66,25 @@ func (c *CORS) SeCustomHeaders(header *Header) {
 func (l *Listener) CORS(c *CORS) {
 	l.config.CORS = c
 }
+
+// corsKey - context key of the CORS configuration of the listener serving a request
+type corsKey struct{}
+
+// corsMiddleware - stores the CORS configuration in the request context, so the OPTIONS handler of the router follows the configuration applied to the listener
+func corsMiddleware(c *CORS) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), corsKey{}, c)))
+		})
+	}
+}
+
+// corsOptionsHandler - responds to OPTIONS with the CORS configuration of the listener serving the request
+func corsOptionsHandler(w http.ResponseWriter, r *http.Request) {
+	c, ok := r.Context().Value(corsKey{}).(*CORS)
+	if !ok {
+		c = NewCORS() // router used outside of a listener
+	}
+
+	optionsHandler(c)(w, r)
+}
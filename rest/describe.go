@@ -0,0 +1,82 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package rest
+
+import (
+	"github.com/handletec/listener/describe"
+)
+
+// Describe - describes the endpoints, TLS settings, middleware stack, CORS policy and routes of this listener
+func (l *Listener) Describe() (d describe.Description) {
+
+	l.mu.Lock()
+	config, header, endpoints, addrs := l.config, l.header, l.endpoints, l.addrs
+	l.mu.Unlock()
+
+	d.Name = l.Name()
+	d.Protocol = "REST"
+
+	for i, ep := range endpoints {
+		e := describe.Endpoint{
+			Name: l.endpointName(i),
+			TLS:  describe.TLSOf(ep.tlsConfig),
+		}
+
+		switch {
+		case i < len(addrs) && addrs[i].Network() == "unix":
+			e.Address = UnixPrefix + addrs[i].String()
+		case i < len(addrs):
+			e.Address = addrs[i].String()
+		case len(ep.socketPath) > 0:
+			e.Address = ep.address
+		default:
+			e.Address = describe.Address(nil, ep.address, ep.port)
+		}
+
+		d.Endpoints = append(d.Endpoints, e)
+	}
+
+	if len(d.Endpoints) > 0 {
+		d.Address = d.Endpoints[0].Address
+		d.TLS = d.Endpoints[0].TLS
+	}
+
+	if len(d.Endpoints) < 2 {
+		d.Endpoints = nil // a single endpoint is already described by the address and TLS settings
+	}
+
+	if nil == config {
+		return
+	}
+
+	if nil != config.CORS {
+		d.CORS = &describe.CORS{
+			Origins:          config.CORS.AllowedOrigins,
+			Methods:          config.CORS.AllowedMethods,
+			Headers:          config.CORS.AllowedHeaders,
+			MaxAge:           config.CORS.MaxAge,
+			AllowCredentials: config.CORS.AllowCredentials,
+		}
+
+		for _, m := range l.middlewares(config, header) {
+			d.Middleware = append(d.Middleware, m.name)
+		}
+	}
+
+	d.Routes = l.Routes()
+
+	return
+}
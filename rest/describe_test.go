@@ -0,0 +1,71 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package rest
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDescribeMiddleware(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		config func(cfg *Config)
+		want   []string
+	}{
+		{
+			name:   "default",
+			config: func(cfg *Config) {},
+			want:   []string{"Logger", "NoCache", "Throttle(4096)", "Timeout(15s)", "SetContentType(json)", "AllowContentType(application/json)", "Recoverer", "CORS", "Headers", "CORSOptions"},
+		},
+		{
+			// rejected by `Start`, but must still be described rather than constructing a throttle that panics
+			name:   "invalid RPS and timeout",
+			config: func(cfg *Config) { cfg.RPS = 0; cfg.Timeout = 0 },
+			want:   []string{"Logger", "NoCache", "Throttle(0)", "Timeout(0s)", "SetContentType(json)", "AllowContentType(application/json)", "Recoverer", "CORS", "Headers", "CORSOptions"},
+		},
+		{
+			name: "optional middlewares",
+			config: func(cfg *Config) {
+				cfg.EnableCompress(true)
+				cfg.SetTrustedProxies("10.0.0.0/8")
+				cfg.SetAuth(nil, nil)
+				cfg.RPS = 10
+				cfg.Timeout = time.Second
+			},
+			want: []string{"Logger", "Compress", "RealIP", "NoCache", "Throttle(10)", "Timeout(1s)", "SetContentType(json)", "AllowContentType(application/json)", "Recoverer", "CORS", "Headers", "CORSOptions"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig()
+			tt.config(cfg)
+
+			l := New()
+			if err := l.SetConfig(cfg); nil != err {
+				t.Fatal(err)
+			}
+
+			d := l.Describe()
+			if !reflect.DeepEqual(d.Middleware, tt.want) {
+				t.Errorf("middleware = %v, want %v", d.Middleware, tt.want)
+			}
+		})
+	}
+}
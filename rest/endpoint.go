@@ -0,0 +1,165 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package rest
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/handletec/listener/socket"
+)
+
+// endpoint - address the listener binds to, every endpoint serves the same router with its own TLS configuration
+type endpoint struct {
+	name       string // matches the socket passed through socket activation
+	address    string
+	port       int
+	socketPath string // path of the Unix socket to listen on instead of a TCP port
+	tlsConfig  *tls.Config
+}
+
+// newEndpoint - create new instance of an endpoint. An address starting with `unix://` listens on the Unix socket path that follows it, the port is ignored
+func newEndpoint(address string, port int, tlsConfig *tls.Config) (ep *endpoint, err error) {
+
+	if len(address) == 0 {
+		address = DefaultAddr // if no address is given we have it listen on all IPv4 and IPv6 interfaces
+	}
+
+	ep = new(endpoint)
+
+	if path, ok := strings.CutPrefix(address, UnixPrefix); ok {
+		if len(path) == 0 {
+			return nil, errors.New("unix socket path cannot be left blank")
+		}
+
+		ep.socketPath = path
+		port = 0 // ports do not apply to Unix sockets
+	} else {
+		switch {
+		case port == 0:
+			port = DefaultPort // default port if none is given
+		case port == EphemeralPort:
+			port = 0 // the OS assigns a free port when binding to port 0
+		case port < 0 || port > 65535:
+			return nil, fmt.Errorf("invalid port %d", port)
+		}
+	}
+
+	ep.address = address
+	ep.port = port
+	ep.tlsConfig = tlsConfig
+
+	return
+}
+
+// AddEndpoint - serves the same router on an additional address and port, with its own TLS configuration (nil serves plain HTTP).
+// Must be called after `Init`, which removes all additional endpoints, and before `Start`
+func (l *Listener) AddEndpoint(address string, port int, tlsConfig *tls.Config) (err error) {
+
+	ep, err := newEndpoint(address, port, tlsConfig)
+	if nil != err {
+		return fmt.Errorf("REST add endpoint: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.endpoints) == 0 {
+		return errors.New("REST add endpoint: listener is not initialized")
+	}
+
+	l.endpoints = append(l.endpoints, ep)
+
+	return
+}
+
+// listen - binds with the listen function of the configuration if there is one, otherwise uses the socket passed by the supervisor under
+// this endpoint's name if there is one, otherwise binds to the configured address and port
+func (ep *endpoint) listen(config *Config) (ln net.Listener, activated bool, err error) {
+
+	address := net.JoinHostPort(strings.Trim(ep.address, "[]"), strconv.Itoa(ep.port))
+
+	if nil != config.Listen {
+		if len(ep.socketPath) > 0 {
+			ln, err = config.Listen("unix", ep.socketPath) // socket permissions are left to the listen function
+		} else {
+			ln, err = config.Listen("tcp", address)
+		}
+
+		return
+	}
+
+	ln, err = socket.Listener(ep.name)
+	if nil != err || nil != ln {
+		return ln, nil != ln, err
+	}
+
+	if len(ep.socketPath) > 0 {
+		ln, err = ep.listenUnix(config)
+		return
+	}
+
+	ln, err = net.Listen("tcp", address)
+	return
+}
+
+// tlsEnabled - determines if the TLS configuration of this endpoint is able to serve certificates
+func (ep *endpoint) tlsEnabled() (enabled bool) {
+	if nil == ep.tlsConfig {
+		return false
+	}
+
+	return len(ep.tlsConfig.Certificates) > 0 || nil != ep.tlsConfig.GetCertificate || nil != ep.tlsConfig.GetConfigForClient
+}
+
+// serverTLS - returns the TLS configuration of this endpoint prepared the same way `http.Server.ServeTLS` does, offering HTTP/2 to clients
+func (ep *endpoint) serverTLS() (cfg *tls.Config) {
+
+	cfg = ep.tlsConfig.Clone()
+
+	for _, proto := range []string{"h2", "http/1.1"} {
+		found := false
+		for _, p := range cfg.NextProtos {
+			if p == proto {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			cfg.NextProtos = append(cfg.NextProtos, proto)
+		}
+	}
+
+	return
+}
+
+// scheme - returns the scheme used to log the address of this endpoint
+func (ep *endpoint) scheme(addr net.Addr) (scheme string) {
+
+	switch {
+	case addr.Network() == "unix":
+		return "unix"
+	case ep.tlsEnabled():
+		return "https"
+	}
+
+	return "http"
+}
@@ -17,18 +17,26 @@ package rest
 
 import (
 	"compress/flate"
+	"context"
 	"crypto/tls"
 	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
 	"github.com/go-chi/render"
+	"github.com/handletec/listener/internal/accept"
+	"github.com/handletec/listener/lifecycle"
+	"github.com/handletec/listener/socket"
 	slogchi "github.com/samber/slog-chi"
 	slogformatter "github.com/samber/slog-formatter"
 )
@@ -39,16 +47,25 @@ const (
 
 	// DefaultPort - default port to listen on
 	DefaultPort = 8081
+
+	// EphemeralPort - let the OS pick any available port, use `Addr` to find the port that was bound
+	EphemeralPort = -1
 )
 
 // Listener - implementation of REST listener
 type Listener struct {
-	address   string
-	port      int
-	tlsConfig *tls.Config
+	lifecycle.Lifecycle // state, hooks and readiness, reported through `Status`, `Subscribe` and `Ready`
+
+	name      string
+	endpoints []*endpoint // addresses to listen on, the first one is given to `Init`
 	logger    *slog.Logger
 	config    *Config
 	header    *Header
+	server    *http.Server
+	addrs     []net.Addr              // bound address of every endpoint
+	mux       atomic.Pointer[chi.Mux] // middleware stack and router serving requests, swapped by `ApplyConfig`
+	mu        sync.Mutex              // protects config, header, endpoints, server and addrs
+	applyMu   sync.Mutex              // serializes building the middleware stack
 }
 
 // New - create new instance of the REST listener
@@ -59,23 +76,39 @@ func New() (l *Listener) {
 
 // Name - returns the name of this listener
 func (l *Listener) Name() (str string) {
-	return "REST"
+	if len(l.name) == 0 {
+		return "REST"
+	}
+
+	return l.name
 }
 
-// Init - initializes this listener with any necessary configuration parameters
+// SetName - sets a custom name for this listener, used in logs and to match sockets passed through socket activation
+func (l *Listener) SetName(name string) {
+	l.name = name
+}
+
+// Init - initializes this listener with any necessary configuration parameters.
+// An address starting with `unix://` listens on the Unix socket path that follows it, the port is ignored
 func (l *Listener) Init(logger *slog.Logger, address string, port int, tlsConfig *tls.Config) (err error) {
 
-	if len(address) == 0 {
-		address = DefaultAddr // if no address is given we have it listen on all IPv4 and IPv6 interfaces
-	}
+	defer func() {
+		if nil != err {
+			l.SetState(l.Name(), lifecycle.Failed, err)
+			return
+		}
+		l.SetState(l.Name(), lifecycle.Initialized, nil)
+	}()
 
-	if port == 0 {
-		port = DefaultPort // default port if none is given
+	ep, err := newEndpoint(address, port, tlsConfig)
+	if nil != err {
+		return fmt.Errorf("REST init: %w", err)
 	}
 
-	l.address = address
-	l.port = port
-	l.tlsConfig = tlsConfig
+	l.mu.Lock()
+	l.endpoints = []*endpoint{ep} // additional endpoints are added once initialized
+	l.addrs = nil
+	l.mu.Unlock()
 
 	if nil == logger {
 		// if no logger is given, create a new instance
@@ -100,30 +133,270 @@ func (l *Listener) Init(logger *slog.Logger, address string, port int, tlsConfig
 		l.config = NewConfig()
 	}
 
+	if err = l.Hooks().Run(context.Background(), lifecycle.EventInit); nil != err {
+		return fmt.Errorf("REST init: %w", err)
+	}
+
 	return
 }
 
-// SetConfig - sets configuration details for this lietener
+// SetConfig - sets configuration details for this listener, use `ApplyConfig` to change the configuration of a running listener
 func (l *Listener) SetConfig(config any) (err error) {
-	l.config = config.(*Config)
+	cfg, ok := config.(*Config)
+	if !ok {
+		return fmt.Errorf("REST setconfig: expected *rest.Config, received %T", config)
+	}
+
+	l.mu.Lock()
+	l.config = cfg
+	l.mu.Unlock()
+
 	return
 }
 
-// Start - starts this listener
-func (l *Listener) Start() (err error) {
+// Config - returns the configuration in use by this listener, including one swapped in by `ApplyConfig`
+func (l *Listener) Config() (config any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.config
+}
+
+// Start - starts this listener and blocks until it stops, cancelling ctx gracefully shuts down the listener
+func (l *Listener) Start(ctx context.Context) (err error) {
 	l.logger.Info("listener starting", "listener", l.Name())
+	l.SetState(l.Name(), lifecycle.Starting, nil)
+
+	defer func() {
+		if nil != err {
+			l.SetState(l.Name(), lifecycle.Failed, err)
+		}
+	}()
+
+	// build the middleware stack while holding applyMu, so a configuration applied concurrently is never lost
+	l.applyMu.Lock()
+	l.mu.Lock()
+	config, header := l.config, l.header
+	l.mu.Unlock()
+
+	err = config.validate()
+	if nil != err {
+		l.applyMu.Unlock()
+		return fmt.Errorf("REST start: %w", err)
+	}
+
+	mux, err := l.newMux(config, header)
+	if nil != err {
+		l.applyMu.Unlock()
+		return fmt.Errorf("REST start: %w", err)
+	}
+
+	l.mux.Store(mux)
+	l.applyMu.Unlock()
 
-	if nil == l.config.router {
-		return errors.New("REST start: no HTTP routers configured")
+	if err = l.Hooks().Run(ctx, lifecycle.EventBeforeStart); nil != err {
+		return fmt.Errorf("start rest: %w", err)
 	}
 
+	l.mu.Lock()
+	endpoints := l.endpoints
+	l.mu.Unlock()
+
+	if len(endpoints) == 0 {
+		return errors.New("start rest: listener is not initialized")
+	}
+
+	lns, err := l.listen(config, endpoints)
+	if nil != err {
+		return fmt.Errorf("start rest: %w", err)
+	}
+
+	server := &http.Server{
+		Addr:        lns[0].Addr().String(),
+		Handler:     http.HandlerFunc(l.serveHTTP), // always serves with the most recently applied configuration
+		ConnContext: withPeer,
+	}
+
+	addrs := make([]net.Addr, len(lns))
+	for i, ln := range lns {
+		addrs[i] = ln.Addr()
+	}
+
+	l.mu.Lock()
+	l.server = server
+	l.addrs = addrs
+	l.mu.Unlock()
+
+	// every endpoint is served by the same server, so a shutdown drains all of them
+	serveErr := make(chan error, len(lns))
+	served := func(n int) {
+		for i := 0; i < n; i++ {
+			<-serveErr
+		}
+	}
+
+	for i, ln := range lns {
+		ep := endpoints[i]
+
+		// allow the socket to be passed to a new process during a binary upgrade
+		socket.Track(ep.name, ln)
+		defer socket.Untrack(ln)
+
+		l.logger.Info("listener started", "listener", l.Name(), "address", ep.scheme(ln.Addr())+"://"+ln.Addr().String(), "tls", strconv.FormatBool(ep.tlsEnabled()))
+
+		chain := accept.Chain{IPFilter: config.IPFilter, ConnLimit: config.ConnLimit, ProxyProtocol: config.ProxyProtocol}
+		if ep.tlsEnabled() {
+			chain.TLS = ep.serverTLS() // certificates are provided by the TLS configuration of the endpoint
+		}
+
+		ln = chain.Wrap(ln, l.logger.With("listener", ep.name))
+
+		go func(ln net.Listener) {
+			serveErr <- server.Serve(ln)
+		}(ln)
+	}
+
+	if err = l.Started(ctx, l.Name()); nil != err {
+		// a started hook failed, drain the endpoints that are already serving requests so nothing is served half set up
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), l.shutdownTimeout())
+		defer cancel()
+
+		l.Shutdown(shutdownCtx)
+		served(len(lns))
+
+		return fmt.Errorf("start rest: %w", err)
+	}
+
+	select {
+	case err = <-serveErr:
+		if nil != err && !errors.Is(err, http.ErrServerClosed) {
+			// an endpoint stopped without a shutdown, stop serving on the other endpoints as well
+			l.mu.Lock()
+			if l.server == server {
+				l.server = nil
+			}
+			l.mu.Unlock()
+
+			server.Close()
+
+			// the other endpoints were closed above, the stopped hooks still run to release what the started hooks acquired
+			err = errors.Join(err, l.Hooks().Run(context.WithoutCancel(ctx), lifecycle.EventStopped))
+		}
+		served(len(lns) - 1)
+	case <-ctx.Done():
+		// the caller no longer wants this listener running, drain in-flight requests before returning
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), l.shutdownTimeout())
+		defer cancel()
+
+		err = l.Shutdown(shutdownCtx)
+		served(len(lns))
+	}
+
+	if nil != err && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("start rest: %w", err)
+	}
+
+	return nil
+}
+
+// listen - binds every endpoint, closing the ones already bound if any of them fails
+func (l *Listener) listen(config *Config, endpoints []*endpoint) (lns []net.Listener, err error) {
+
+	for i, ep := range endpoints {
+		ep.name = l.endpointName(i)
+
+		ln, activated, e := ep.listen(config)
+		if nil != e {
+			for _, ln := range lns {
+				ln.Close()
+			}
+
+			return nil, e
+		}
+
+		if activated {
+			l.logger.Info("listener using activated socket", "listener", l.Name(), "address", ln.Addr().String())
+		}
+
+		lns = append(lns, ln)
+	}
+
+	return
+}
+
+// endpointName - returns the name of the endpoint at position i. The first endpoint keeps the name of the listener, so socket activation works as it does for a single endpoint
+func (l *Listener) endpointName(i int) (name string) {
+	if i == 0 {
+		return l.Name()
+	}
+
+	return fmt.Sprintf("%s-%d", l.Name(), i)
+}
+
+// middlewareEntry - middleware run for every request, named so the stack can be described without constructing any of the middlewares
+type middlewareEntry struct {
+	name  string
+	build func() func(http.Handler) http.Handler // only called when the stack is built to serve requests
+}
+
+// newMux - builds the middleware stack around the router of the given configuration
+func (l *Listener) newMux(config *Config, header *Header) (mux *chi.Mux, err error) {
+
+	defer func() {
+		// chi panics on conflicting routes, report it as an invalid configuration instead
+		if r := recover(); nil != r {
+			mux = nil
+			err = fmt.Errorf("build router: %v", r)
+		}
+	}()
+
 	router := chi.NewRouter()
 
-	router.Use(slogchi.New(l.logger.WithGroup(l.Name())))
+	for _, m := range l.middlewares(config, header) {
+		router.Use(m.build())
+	}
+
+	//router.Mount("/", config.router.r) // mount the root to the given handler
+
+	config.router.mount()              // mount all the paths, only done the first time a router is used
+	router.Mount("/", config.router.r) // mount the root to the given handler
+
+	/*
+		for _, route := range config.router.r.Routes() {
+			fmt.Println(route.Pattern, route.SubRoutes)
+		}
+	*/
+
+	return router, nil
+}
+
+// middlewares - returns the middleware stack of the given configuration, in the order the middlewares run. Middlewares are only
+// constructed once the stack is built, so describing a configuration `Start` would reject never panics
+func (l *Listener) middlewares(config *Config, header *Header) (stack []middlewareEntry) {
+
+	use := func(name string, build func() func(http.Handler) http.Handler) {
+		stack = append(stack, middlewareEntry{name: name, build: build})
+	}
+
+	// fixed - middleware that needs no construction
+	fixed := func(fn func(http.Handler) http.Handler) func() func(http.Handler) http.Handler {
+		return func() func(http.Handler) http.Handler {
+			return fn
+		}
+	}
+
+	use("Logger", func() func(http.Handler) http.Handler {
+		logger := l.logger
+		if nil == logger {
+			logger = slog.Default() // initialized without a logger
+		}
+
+		return slogchi.New(logger.WithGroup(l.Name()))
+	})
 
 	/*
 		// print the requests information
-		if l.config.Log {
+		if config.Log {
 			//router.Use(middleware.Logger)
 			//router.Use(slogchi.New(l.logger)) // this throws an panic, must troubleshoot
 
@@ -131,77 +404,140 @@ func (l *Listener) Start() (err error) {
 		}
 	*/
 
-	if l.config.compress {
-		router.Use(middleware.Compress(flate.DefaultCompression)) // compress data for smaller size
+	if config.compress {
+		use("Compress", func() func(http.Handler) http.Handler {
+			return middleware.Compress(flate.DefaultCompression) // compress data for smaller size
+		})
 	}
 
-	router.Use(middleware.RealIP)
+	// client addresses forwarded in headers are only trusted from known reverse proxies, never alongside the PROXY protocol, which already
+	// recovered the address of the client and would otherwise let it be overwritten by a header the client sent itself
+	if len(config.trustedProxies) > 0 && nil == config.ProxyProtocol {
+		use("RealIP", func() func(http.Handler) http.Handler {
+			return realIPMiddleware(config.trustedProxies)
+		})
+	}
 
 	// (optional) - do not cache requests
-	router.Use(middleware.NoCache)
+	use("NoCache", fixed(middleware.NoCache))
 
-	router.Use(middleware.Throttle(l.config.RPS)) // restrict number of concurrent requests per second
+	// restrict number of concurrent requests per second
+	use(fmt.Sprintf("Throttle(%d)", config.RPS), func() func(http.Handler) http.Handler {
+		return middleware.Throttle(config.RPS)
+	})
 
 	// Set a timeout value on the request context (ctx), that will signal
 	// through ctx.Done() that the request has timed out and further
 	// processing should be stopped.
-	router.Use(middleware.Timeout(l.config.Timeout))
+	use(fmt.Sprintf("Timeout(%s)", config.Timeout), func() func(http.Handler) http.Handler {
+		return middleware.Timeout(config.Timeout)
+	})
 
-	router.Use(render.SetContentType(render.ContentTypeJSON))
-	router.Use(middleware.AllowContentType("application/json")) // only accept JSON content type
-	router.Use(middleware.Recoverer)
+	use("SetContentType(json)", fixed(render.SetContentType(render.ContentTypeJSON)))
+	use("AllowContentType(application/json)", func() func(http.Handler) http.Handler {
+		return middleware.AllowContentType("application/json") // only accept JSON content type
+	})
+	use("Recoverer", fixed(middleware.Recoverer))
 
 	// CORS configuration
-	router.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   l.config.CORS.AllowedOrigins,
-		AllowedMethods:   l.config.CORS.AllowedMethods,
-		AllowedHeaders:   l.config.CORS.AllowedHeaders,
-		AllowCredentials: l.config.CORS.AllowCredentials,
-		ExposedHeaders:   l.config.CORS.AllowedHeaders,
-		MaxAge:           l.config.CORS.MaxAge, // Maximum value not ignored by any of major browsers
-		Debug:            l.config.CORS.Debug,
-	}))
+	use("CORS", func() func(http.Handler) http.Handler {
+		return cors.Handler(cors.Options{
+			AllowedOrigins:   config.CORS.AllowedOrigins,
+			AllowedMethods:   config.CORS.AllowedMethods,
+			AllowedHeaders:   config.CORS.AllowedHeaders,
+			AllowCredentials: config.CORS.AllowCredentials,
+			ExposedHeaders:   config.CORS.AllowedHeaders,
+			MaxAge:           config.CORS.MaxAge, // Maximum value not ignored by any of major browsers
+			Debug:            config.CORS.Debug,
+		})
+	})
+
+	use("Headers", func() func(http.Handler) http.Handler {
+		return headerMiddleware(header)
+	})
+
+	if nil != config.authn || nil != config.authz {
+		use("Auth", func() func(http.Handler) http.Handler {
+			return Auth(config.authn, config.authz) // authentication and authorization for every request of this listener
+		})
+	}
 
-	router.Use(headerMiddleware(l.header))
+	// OPTIONS requests are answered by the router with the CORS configuration of the listener serving the request
+	use("CORSOptions", func() func(http.Handler) http.Handler {
+		return corsMiddleware(config.CORS)
+	})
 
-	// handle OPTIONS request, usually for CORS, though the CORS handler above does the heavy lifting for us already
-	l.config.router.r.MethodFunc(MethodOptions.String(), PatternAll, optionsHandler(l.config.CORS))
+	return
+}
 
-	//router.Mount("/", l.config.router.r) // mount the root to the given handler
+// Shutdown - stops accepting new connections and waits for in-flight requests to complete or for ctx to expire, whichever comes first
+func (l *Listener) Shutdown(ctx context.Context) (err error) {
 
-	l.config.router.mount()              // mount all the paths
-	router.Mount("/", l.config.router.r) // mount the root to the given handler
+	l.mu.Lock()
+	server := l.server
+	l.server = nil // only the first call drains and runs the hooks
+	l.mu.Unlock()
 
-	/*
-		for _, route := range l.config.router.r.Routes() {
-			fmt.Println(route.Pattern, route.SubRoutes)
-		}
-	*/
+	if nil == server {
+		return // listener was never started or is already shutdown, nothing to shutdown
+	}
 
-	address := fmt.Sprintf("%s:%d", l.address, l.port)
+	l.logger.Info("listener shutting down", "listener", l.Name())
+	l.SetState(l.Name(), lifecycle.Draining, nil)
 
-	if nil != l.tlsConfig && len(l.tlsConfig.Certificates) > 0 {
-		l.logger.Info("listener started", "listener", l.Name(), "address", "https://"+address, "tls", "true")
+	// deregister from e.g. service discovery while every endpoint still serves, so no new requests are routed here once draining starts
+	hookErr := l.Hooks().Run(ctx, lifecycle.EventShutdown)
 
-		// start HTTPS server
-		server := &http.Server{
-			Addr:      address,
-			Handler:   router,
-			TLSConfig: l.tlsConfig,
-		}
+	err = server.Shutdown(ctx)
+	if nil != err {
+		err = fmt.Errorf("shutdown rest: %w", err)
+	}
 
-		err = server.ListenAndServeTLS("", "")
-		if nil != err {
-			return fmt.Errorf("start rest: %w", err)
-		}
+	l.logger.Info("listener stopped", "listener", l.Name())
+
+	// drained every endpoint, stopped hooks run even when in-flight requests did not complete in time
+	err = l.Stopped(ctx, l.Name(), errors.Join(err, hookErr))
+
+	return
+}
+
+// serveHTTP - passes the request to the most recently applied middleware stack and router
+func (l *Listener) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	l.mux.Load().ServeHTTP(w, r)
+}
 
-	} else {
-		l.logger.Info("listener started", "listener", l.Name(), "address", "http://"+address, "tls", "false")
+// shutdownTimeout - returns the time to wait for in-flight requests of the most recently applied configuration
+func (l *Listener) shutdownTimeout() (timeout time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
 
-		// start normal HTTP server
-		err = http.ListenAndServe(address, router)
+	return l.config.ShutdownTimeout
+}
 
+// Addr - returns the address this listener is bound to, or nil if it has not been started. With multiple endpoints it is the address given to `Init`
+func (l *Listener) Addr() (addr net.Addr) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.addrs) == 0 {
+		return nil
 	}
 
+	return l.addrs[0]
+}
+
+// Addrs - returns the addresses of every endpoint in the order they were added, or nil if the listener has not been started
+func (l *Listener) Addrs() (addrs []net.Addr) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return append(addrs, l.addrs...)
+}
+
+// Status - returns the lifecycle state of this REST listener under its current name, which may have been changed by `SetName`
+func (l *Listener) Status() (status lifecycle.Status) {
+	status = l.Lifecycle.Status()
+	status.Name = l.Name()
+
 	return
 }
@@ -0,0 +1,311 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package rest_test
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/handletec/listener"
+	"github.com/handletec/listener/listenertest"
+	"github.com/handletec/listener/proxyproto"
+	"github.com/handletec/listener/rest"
+)
+
+// newRouter - router under `/api` answering GET requests to `/ping` with the body
+func newRouter(t *testing.T, body string) (router *rest.Router) {
+	t.Helper()
+
+	handler := rest.NewNewHandler()
+	if err := handler.Set(rest.MethodGet, "/ping", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}); nil != err {
+		t.Fatal(err)
+	}
+
+	router = rest.NewRouter("/api")
+	router.SetHandler(handler)
+
+	return
+}
+
+// newListener - REST listener serving the router in memory
+func newListener(t *testing.T, router *rest.Router) (l *rest.Listener, s *listenertest.Server) {
+	t.Helper()
+
+	cfg := rest.NewConfig()
+	cfg.SetRouter(router)
+
+	l = rest.New()
+	if err := l.SetConfig(cfg); nil != err {
+		t.Fatal(err)
+	}
+
+	opts := listenertest.NewOptions()
+	opts.InMemory = true
+
+	return l, listenertest.Start(t, l, opts)
+}
+
+func TestShutdownDrainsRequests(t *testing.T) {
+
+	started, release := make(chan struct{}), make(chan struct{})
+
+	handler := rest.NewNewHandler()
+	if err := handler.Set(rest.MethodGet, "/slow", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.Write([]byte("done"))
+	}); nil != err {
+		t.Fatal(err)
+	}
+
+	router := rest.NewRouter("/api")
+	router.SetHandler(handler)
+
+	l, s := newListener(t, router)
+
+	type result struct {
+		body string
+		err  error
+	}
+	responses := make(chan result, 1)
+	go func() {
+		resp, err := s.Client.Get(s.URL + "/api/slow")
+		if nil != err {
+			responses <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		responses <- result{string(body), err}
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- l.Shutdown(ctx)
+	}()
+
+	// the request in flight holds up the shutdown until it completes
+	select {
+	case err := <-stopped:
+		t.Fatalf("Shutdown() = %v while a request was in flight, expected it to wait", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if state := l.Status().State; state != listener.StateDraining {
+		t.Errorf("state while draining = %s, expected %s", state, listener.StateDraining)
+	}
+
+	close(release)
+
+	if r := <-responses; nil != r.err || r.body != "done" {
+		t.Errorf("in-flight request = %q, %v, expected %q", r.body, r.err, "done")
+	}
+
+	if err := <-stopped; nil != err {
+		t.Fatalf("Shutdown() = %v", err)
+	}
+
+	if _, err := s.Client.Get(s.URL + "/api/slow"); nil == err {
+		t.Error("request served once shut down")
+	}
+
+	if err := l.Shutdown(ctx); nil != err {
+		t.Errorf("second Shutdown() = %v, expected nil", err)
+	}
+}
+
+func TestSetConfig(t *testing.T) {
+
+	cfg := rest.NewConfig()
+
+	tests := []struct {
+		name    string
+		config  any
+		wantErr bool
+	}{
+		{name: "REST configuration", config: cfg},
+		{name: "configuration of another protocol", config: struct{ RPS int }{RPS: 10}, wantErr: true},
+		{name: "configuration by value", config: *rest.NewConfig(), wantErr: true},
+		{name: "no configuration", config: nil, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := rest.New()
+			if err := l.SetConfig(tt.config); (nil != err) != tt.wantErr {
+				t.Fatalf("SetConfig() = %v, expected error %t", err, tt.wantErr)
+			}
+
+			if got, _ := l.Config().(*rest.Config); !tt.wantErr && got != cfg {
+				t.Errorf("Config() = %p, expected %p", got, cfg)
+			}
+		})
+	}
+}
+
+func TestApplyConfig(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		config     func(t *testing.T) (cfg *rest.Config, header *rest.Header)
+		wantErr    bool
+		wantBody   string
+		wantHeader string // value of `X-Version`, empty when it must not be set
+	}{
+		{
+			name: "custom header applied",
+			config: func(t *testing.T) (cfg *rest.Config, header *rest.Header) {
+				cfg = rest.NewConfig()
+				cfg.SetRouter(newRouter(t, "v1"))
+
+				header = rest.NewHeader()
+				header.Add("X-Version", "2")
+
+				return
+			},
+			wantBody:   "v1",
+			wantHeader: "2",
+		},
+		{
+			name: "router replaced",
+			config: func(t *testing.T) (cfg *rest.Config, header *rest.Header) {
+				cfg = rest.NewConfig()
+				cfg.SetRouter(newRouter(t, "v2"))
+
+				return cfg, nil
+			},
+			wantBody: "v2",
+		},
+		{
+			name: "invalid RPS rejected",
+			config: func(t *testing.T) (cfg *rest.Config, header *rest.Header) {
+				cfg = rest.NewConfig()
+				cfg.RPS = 0
+				cfg.SetRouter(newRouter(t, "v2"))
+
+				header = rest.NewHeader()
+				header.Add("X-Version", "2")
+
+				return
+			},
+			wantErr:  true,
+			wantBody: "v1",
+		},
+		{
+			name: "missing router rejected",
+			config: func(t *testing.T) (cfg *rest.Config, header *rest.Header) {
+				return rest.NewConfig(), nil
+			},
+			wantErr:  true,
+			wantBody: "v1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, s := newListener(t, newRouter(t, "v1"))
+
+			s.Get("/api/ping").Status(http.StatusOK).BodyContains("v1").NoHeader("X-Version")
+
+			cfg, header := tt.config(t)
+			if err := l.ApplyConfig(cfg, header); (nil != err) != tt.wantErr {
+				t.Fatalf("ApplyConfig() = %v, expected error %t", err, tt.wantErr)
+			}
+
+			resp := s.Get("/api/ping").Status(http.StatusOK).BodyContains(tt.wantBody)
+			if len(tt.wantHeader) == 0 {
+				resp.NoHeader("X-Version")
+			} else {
+				resp.Header("X-Version", tt.wantHeader)
+			}
+
+			// the rejected configuration must not replace the one in use
+			if tt.wantErr && l.Config().(*rest.Config) == cfg {
+				t.Error("rejected configuration is in use")
+			}
+		})
+	}
+}
+
+func TestProxyProtocolSilentClient(t *testing.T) {
+
+	handler := rest.NewNewHandler()
+	handler.Set(rest.MethodGet, "/addr", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.RemoteAddr))
+	})
+
+	router := rest.NewRouter("/api")
+	router.SetHandler(handler)
+
+	pp, err := proxyproto.NewConfig("127.0.0.1")
+	if nil != err {
+		t.Fatal(err)
+	}
+	pp.HeaderTimeout = 10 * time.Second
+
+	cfg := rest.NewConfig()
+	cfg.ProxyProtocol = pp
+	cfg.SetRouter(router)
+
+	l := rest.New()
+	if err := l.SetConfig(cfg); nil != err {
+		t.Fatal(err)
+	}
+
+	s := listenertest.Start(t, l, nil)
+
+	// a trusted source that connects but never sends its header must not hold up accepting other clients
+	silent, err := net.Dial("tcp", s.Addr().String())
+	if nil != err {
+		t.Fatal(err)
+	}
+	defer silent.Close()
+
+	conn, err := net.Dial("tcp", s.Addr().String())
+	if nil != err {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(2 * time.Second))
+
+	if _, err = io.WriteString(conn, "PROXY TCP4 203.0.113.7 127.0.0.1 5000 80\r\nGET /api/addr HTTP/1.1\r\nHost: api\r\nConnection: close\r\n\r\n"); nil != err {
+		t.Fatal(err)
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if nil != err {
+		t.Fatalf("second client not served while the first one is silent: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(body) != "203.0.113.7:5000" {
+		t.Fatalf("response %d %q, expected %d %q", resp.StatusCode, body, http.StatusOK, "203.0.113.7:5000")
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package rest
+
+import (
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// realIPMiddleware - replaces the address of requests sent by a trusted reverse proxy with the client address it forwarded in the
+// `X-Forwarded-For` or `X-Real-IP` header. Headers of requests from any other peer are ignored, so clients cannot claim another address
+func realIPMiddleware(trusted []netip.Prefix) func(http.Handler) http.Handler {
+
+	isTrusted := func(addr netip.Addr) bool {
+		for _, prefix := range trusted {
+			if prefix.Contains(addr) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			peer, err := netip.ParseAddrPort(r.RemoteAddr)
+			if nil != err || !isTrusted(peer.Addr().Unmap()) {
+				next.ServeHTTP(w, r) // Unix sockets and untrusted peers keep their own address
+				return
+			}
+
+			if ip, ok := forwardedIP(r.Header, isTrusted); ok {
+				r.RemoteAddr = ip.String()
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// forwardedIP - returns the address of the client as seen by the outermost trusted proxy. `X-Forwarded-For` is read from the right, as every
+// proxy appends the address it received the request from, skipping trusted proxies so entries written by the client itself are never used
+func forwardedIP(header http.Header, isTrusted func(netip.Addr) bool) (ip netip.Addr, ok bool) {
+
+	var hops []string
+	for _, value := range header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(value, ",")...)
+	}
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if nil != err {
+			return ip, false // a malformed chain cannot be trusted past this point
+		}
+
+		ip, ok = addr.Unmap(), true
+		if !isTrusted(ip) {
+			return
+		}
+	}
+
+	if ok {
+		return // every hop is a trusted proxy, the first one is the client
+	}
+
+	if addr, err := netip.ParseAddr(strings.TrimSpace(header.Get("X-Real-IP"))); nil == err {
+		return addr.Unmap(), true
+	}
+
+	return
+}
@@ -0,0 +1,125 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/handletec/listener/proxyproto"
+)
+
+func TestRealIPMiddleware(t *testing.T) {
+
+	trusted := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("192.168.1.10/32")}
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		realIP       string
+		want         string
+	}{
+		{name: "untrusted peer spoofing X-Real-IP", remoteAddr: "203.0.113.7:5000", realIP: "198.51.100.1", want: "203.0.113.7:5000"},
+		{name: "untrusted peer spoofing X-Forwarded-For", remoteAddr: "203.0.113.7:5000", forwardedFor: []string{"198.51.100.1"}, want: "203.0.113.7:5000"},
+		{name: "trusted proxy X-Real-IP", remoteAddr: "10.0.0.2:5000", realIP: "198.51.100.1", want: "198.51.100.1"},
+		{name: "trusted proxy X-Forwarded-For", remoteAddr: "192.168.1.10:5000", forwardedFor: []string{"198.51.100.1"}, want: "198.51.100.1"},
+		{name: "client prepended a spoofed hop", remoteAddr: "10.0.0.2:5000", forwardedFor: []string{"1.2.3.4, 198.51.100.1"}, want: "198.51.100.1"},
+		{name: "chain of trusted proxies", remoteAddr: "10.0.0.2:5000", forwardedFor: []string{"198.51.100.1, 10.0.0.3", "10.0.0.4"}, want: "198.51.100.1"},
+		{name: "only trusted hops", remoteAddr: "10.0.0.2:5000", forwardedFor: []string{"10.0.0.3, 10.0.0.4"}, want: "10.0.0.3"},
+		{name: "X-Forwarded-For preferred over X-Real-IP", remoteAddr: "10.0.0.2:5000", forwardedFor: []string{"198.51.100.1"}, realIP: "198.51.100.2", want: "198.51.100.1"},
+		{name: "malformed hop", remoteAddr: "10.0.0.2:5000", forwardedFor: []string{"198.51.100.1, garbage"}, want: "10.0.0.2:5000"},
+		{name: "malformed X-Real-IP", remoteAddr: "10.0.0.2:5000", realIP: "garbage", want: "10.0.0.2:5000"},
+		{name: "IPv4 mapped proxy", remoteAddr: "[::ffff:10.0.0.2]:5000", realIP: "2001:db8::1", want: "2001:db8::1"},
+		{name: "unix socket", remoteAddr: "@", realIP: "198.51.100.1", want: "@"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := realIPMiddleware(trusted)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.RemoteAddr
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, v := range tt.forwardedFor {
+				req.Header.Add("X-Forwarded-For", v)
+			}
+			if len(tt.realIP) > 0 {
+				req.Header.Set("X-Real-IP", tt.realIP)
+			}
+
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if got != tt.want {
+				t.Errorf("RemoteAddr = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrustedProxiesConfig(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		proxies    []string
+		proxyProto bool
+		wantErr    bool
+		wantRealIP bool
+	}{
+		{name: "not trusted by default"},
+		{name: "trusted proxies", proxies: []string{"10.0.0.0/8", "192.168.1.10"}, wantRealIP: true},
+		{name: "invalid proxy", proxies: []string{"10.0.0.0/40"}, wantErr: true},
+		{name: "PROXY protocol", proxyProto: true},
+		{name: "PROXY protocol with trusted proxies", proxies: []string{"10.0.0.0/8"}, proxyProto: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig()
+			cfg.SetRouter(NewRouter("/api"))
+
+			if tt.proxyProto {
+				cfg.ProxyProtocol = new(proxyproto.Config)
+			}
+
+			err := cfg.SetTrustedProxies(tt.proxies...)
+			if nil == err {
+				err = cfg.validate()
+			}
+
+			if (nil != err) != tt.wantErr {
+				t.Fatalf("error = %v, want error %t", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			var realIP bool
+			for _, m := range New().middlewares(cfg, nil) {
+				realIP = realIP || m.name == "RealIP"
+			}
+
+			if realIP != tt.wantRealIP {
+				t.Errorf("RealIP in the middleware stack = %t, want %t", realIP, tt.wantRealIP)
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ package rest
 import (
 	"errors"
 	"net/http"
+	"sync"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -27,6 +28,8 @@ type Router struct {
 	base    string
 	r       chi.Router
 	handler *Handler
+	mounted bool       // set once the handlers are mounted, a router may be applied to a listener more than once
+	mu      sync.Mutex // protects mounted
 }
 
 // NewRouter - create new instance of router under the given base pattern
@@ -65,9 +68,20 @@ func (router *Router) AddHealthCheck(rootPath string, hFn http.HandlerFunc) {
 	router.r.Mount(formatBase(rootPath), hFn)
 }
 
-// mount - makes all handlers not already available under a group to the given defined base pattern
+// mount - makes all handlers not already available under a group to the given defined base pattern, only the first call has any effect
 func (router *Router) mount() (err error) {
 
+	router.mu.Lock()
+	defer router.mu.Unlock()
+
+	if router.mounted {
+		return
+	}
+	router.mounted = true
+
+	// handle OPTIONS request, usually for CORS, though the CORS handler of the listener does the heavy lifting for us already
+	router.r.MethodFunc(MethodOptions.String(), PatternAll, corsOptionsHandler)
+
 	if router.handler == nil {
 		return errors.New("mount: no handlers configured")
 	}
@@ -82,3 +96,12 @@ func NewChi(cr *chi.Mux) (router *Router) {
 	router.r = cr
 	return
 }
+
+// SetRouter - sets the router of a listener whose configuration was created elsewhere, such as one loaded from a configuration file
+func (l *Listener) SetRouter(router *Router) (err error) {
+	if nil == l.config {
+		l.config = NewConfig()
+	}
+
+	return l.config.SetRouter(router)
+}
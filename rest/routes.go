@@ -0,0 +1,64 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package rest
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/handletec/listener/describe"
+)
+
+// Route - method and pattern of a registered handler
+type Route = describe.Route
+
+// Routes - returns every route served by this listener sorted by pattern, including the routes of groups and health checks.
+// Before the listener is started the routes of its configured router are returned
+func (l *Listener) Routes() (routes []Route) {
+
+	var r chi.Routes
+
+	if mux := l.mux.Load(); nil != mux {
+		r = mux
+	} else {
+		l.mu.Lock()
+		config := l.config
+		l.mu.Unlock()
+
+		if nil == config || nil == config.router {
+			return nil
+		}
+
+		config.router.mount() // registers the handlers the same way starting the listener does, an error leaves the router without them
+		r = config.router.r
+	}
+
+	chi.Walk(r, func(method, pattern string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) (err error) {
+		routes = append(routes, Route{Method: method, Pattern: pattern})
+		return
+	})
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].Pattern != routes[j].Pattern {
+			return routes[i].Pattern < routes[j].Pattern
+		}
+
+		return routes[i].Method < routes[j].Method
+	})
+
+	return
+}
@@ -0,0 +1,94 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// UnixPrefix - prefix of an address to listen on a Unix socket path instead of a TCP port, e.g. `unix:///run/app/api.sock`
+const UnixPrefix = "unix://"
+
+// listenUnix - binds to the Unix socket path, removing a stale socket left behind by a process that did not exit cleanly.
+// The socket file is removed again once the listener is closed
+func (ep *endpoint) listenUnix(config *Config) (ln net.Listener, err error) {
+
+	path := ep.socketPath
+	abstract := strings.HasPrefix(path, "@") // Linux abstract sockets have no file on disk
+
+	if !abstract {
+		err = removeStale(path)
+		if nil != err {
+			return nil, err
+		}
+	}
+
+	ln, err = net.Listen("unix", path)
+	if nil != err || abstract {
+		return
+	}
+
+	if config.SocketMode != 0 {
+		if err = os.Chmod(path, config.SocketMode); nil != err {
+			ln.Close()
+			return nil, fmt.Errorf("unix socket '%s': %w", path, err)
+		}
+	}
+
+	if config.SocketUID >= 0 || config.SocketGID >= 0 {
+		if err = os.Chown(path, config.SocketUID, config.SocketGID); nil != err {
+			ln.Close()
+			return nil, fmt.Errorf("unix socket '%s': %w", path, err)
+		}
+	}
+
+	return
+}
+
+// removeStale - removes the socket file at path if no process is accepting connections on it, other files are never removed
+func removeStale(path string) (err error) {
+
+	fi, err := os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	if nil != err {
+		return fmt.Errorf("unix socket '%s': %w", path, err)
+	}
+
+	if fi.Mode().Type() != fs.ModeSocket {
+		return fmt.Errorf("unix socket '%s': file exists and is not a socket", path)
+	}
+
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if nil == err {
+		conn.Close()
+		return fmt.Errorf("unix socket '%s': already in use by another process", path)
+	}
+
+	if err = os.Remove(path); nil != err && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("unix socket '%s': remove stale socket: %w", path, err)
+	}
+
+	return nil
+}
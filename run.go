@@ -0,0 +1,257 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package listener
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/handletec/listener/lifecycle"
+	"github.com/handletec/listener/socket"
+)
+
+// Reloader - optional interface for listeners that are able to reload their configuration without restarting
+type Reloader interface {
+	Reload(ctx context.Context) error
+}
+
+// RunOptions - options controlling how `Run` manages the process lifecycle
+type RunOptions struct {
+	ShutdownTimeout time.Duration                   // maximum time to wait for all listeners to drain after SIGINT or SIGTERM
+	TLS             []*TLSConfigBuilder             // TLS material to be reloaded on SIGHUP
+	Reload          func(ctx context.Context) error // (optional) custom configuration reload to run on SIGHUP
+	Upgrade         bool                            // pass all sockets to a new instance of the executable on SIGUSR2, then drain and exit
+	UpgradeTimeout  time.Duration                   // maximum time to wait for the new instance to become ready
+	Hooks           *Hooks                          // (optional) functions run at each point of the lifecycle of all listeners as a whole
+	Logger          *slog.Logger
+}
+
+// NewRunOptions - creates new instance of run options with sane default values
+func NewRunOptions() (opts *RunOptions) {
+	opts = new(RunOptions)
+	opts.ShutdownTimeout = time.Duration(30 * time.Second)
+	opts.UpgradeTimeout = time.Duration(60 * time.Second)
+	opts.Logger = slog.Default()
+
+	return
+}
+
+// Run - starts all listeners and blocks until they stop. SIGINT, SIGTERM or cancelling ctx gracefully drain all listeners, a second signal aborts the drain.
+// SIGHUP reloads the configured TLS material, listeners implementing `Reloader` and the custom reload function without stopping any listener.
+// When upgrades are enabled, SIGUSR2 starts a new instance of the executable with all sockets passed to it, then drains and exits once it is ready.
+// The hooks in the options are run once for all listeners, init and before start hooks run before any listener starts, started hooks once every listener is ready,
+// shutdown hooks before draining and stopped hooks once every listener stopped
+func Run(ctx context.Context, ls Listeners, opts *RunOptions) (err error) {
+
+	if nil == opts {
+		opts = NewRunOptions()
+	}
+
+	logger := opts.Logger
+	if nil == logger {
+		logger = slog.Default()
+	}
+
+	hooks := opts.Hooks
+	if nil == hooks {
+		hooks = new(Hooks)
+	}
+
+	if err = hooks.Run(ctx, lifecycle.EventInit); nil != err {
+		return fmt.Errorf("run: %w", err)
+	}
+
+	signals := []os.Signal{syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP}
+	if opts.Upgrade && nil != upgradeSignal {
+		signals = append(signals, upgradeSignal)
+	}
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, signals...)
+	defer signal.Stop(sigs)
+
+	if err = hooks.Run(ctx, lifecycle.EventBeforeStart); nil != err {
+		return fmt.Errorf("run: %w", err)
+	}
+
+	// listeners are stopped through `drain` when ctx is cancelled, so shutdown hooks run and the drain timeout applies
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- ls.StartAll(runCtx)
+	}()
+
+	started := make(chan error, 1)
+	go func() {
+		started <- notifyReady(runCtx, ls, hooks, logger)
+	}()
+
+	// stop - drains all listeners and waits for them to stop
+	stop := func() (err error) {
+		hookCtx := context.WithoutCancel(ctx)
+
+		err = errors.Join(hooks.Run(hookCtx, lifecycle.EventShutdown), drain(ls, opts, sigs, logger))
+		cancel()
+
+		if e := <-done; nil != e {
+			err = errors.Join(err, e)
+		}
+
+		err = errors.Join(err, hooks.Run(hookCtx, lifecycle.EventStopped))
+
+		if nil != err {
+			return fmt.Errorf("run: %w", err)
+		}
+
+		logger.Info("shutdown completed")
+		return
+	}
+
+	for {
+		select {
+		case err = <-done:
+			// a listener failed, the remaining listeners were already shutdown
+			return errors.Join(err, hooks.Run(context.WithoutCancel(ctx), lifecycle.EventStopped))
+
+		case err = <-started:
+			started = nil // listeners only become ready once
+
+			if nil != err {
+				logger.Error("started hooks failed, draining listeners", "error", err)
+				return errors.Join(fmt.Errorf("run: %w", err), stop())
+			}
+
+		case <-ctx.Done():
+			logger.Info("shutdown requested", "reason", context.Cause(ctx).Error(), "timeout", opts.ShutdownTimeout.String())
+			return stop()
+
+		case sig := <-sigs:
+			switch sig {
+			case syscall.SIGHUP:
+				logger.Info("reload requested", "signal", sig.String())
+
+				if e := reload(ctx, ls, opts); nil != e {
+					logger.Error("reload failed", "error", e)
+					continue
+				}
+
+				logger.Info("reload completed")
+
+			case upgradeSignal:
+				logger.Info("upgrade requested", "signal", sig.String(), "timeout", opts.UpgradeTimeout.String())
+
+				upgradeCtx, upgradeCancel := context.WithTimeout(ctx, opts.UpgradeTimeout)
+				e := socket.Upgrade(upgradeCtx)
+				upgradeCancel()
+
+				if nil != e {
+					logger.Error("upgrade failed, continuing with the current process", "error", e)
+					continue
+				}
+
+				logger.Info("upgrade completed, draining listeners", "timeout", opts.ShutdownTimeout.String())
+				return stop()
+
+			default:
+				logger.Info("shutdown requested", "signal", sig.String(), "timeout", opts.ShutdownTimeout.String())
+				return stop()
+			}
+		}
+	}
+}
+
+// notifyReady - runs the started hooks once all listeners are ready, then tells the process that started this one during an upgrade that it is ready
+func notifyReady(ctx context.Context, ls Listeners, hooks *Hooks, logger *slog.Logger) (err error) {
+
+	for _, l := range ls {
+		select {
+		case <-l.Ready():
+		case <-ctx.Done():
+			return nil
+		}
+	}
+
+	if err = hooks.Run(ctx, lifecycle.EventStarted); nil != err {
+		return err
+	}
+
+	if e := socket.Ready(); nil != e {
+		logger.Error("upgrade ready notification failed", "error", e)
+	}
+
+	return nil
+}
+
+// drain - shuts down all listeners within the configured timeout, aborting early if another termination signal is received
+func drain(ls Listeners, opts *RunOptions, sigs <-chan os.Signal, logger *slog.Logger) (err error) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.ShutdownTimeout)
+	defer cancel()
+
+	go func() {
+		for {
+			select {
+			case sig := <-sigs:
+				if sig == syscall.SIGHUP {
+					continue // reloading while draining is pointless
+				}
+				logger.Warn("shutdown aborted, forcing exit", "signal", sig.String())
+				cancel()
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ls.ShutdownAll(ctx)
+}
+
+// reload - reloads TLS material, listeners and custom configuration, collecting all errors encountered
+func reload(ctx context.Context, ls Listeners, opts *RunOptions) (err error) {
+
+	var errs []error
+
+	for _, t := range opts.TLS {
+		if e := t.Reload(); nil != e {
+			errs = append(errs, e)
+		}
+	}
+
+	for _, l := range ls {
+		if r, ok := l.(Reloader); ok {
+			if e := r.Reload(ctx); nil != e {
+				errs = append(errs, fmt.Errorf("%s: %w", l.Name(), e))
+			}
+		}
+	}
+
+	if nil != opts.Reload {
+		if e := opts.Reload(ctx); nil != e {
+			errs = append(errs, e)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,205 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package socket
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// listenFdsStart - first file descriptor passed by systemd, following stdin, stdout and stderr
+	listenFdsStart = 3
+
+	// UnknownName - name given to sockets passed without a matching entry in LISTEN_FDNAMES
+	UnknownName = "unknown"
+
+	// readyEnv - environment variable holding the descriptor a process started by `Upgrade` uses to report it is ready
+	readyEnv = "LISTENER_UPGRADE_READY_FD"
+)
+
+var (
+	activated     map[string][]*os.File // sockets passed to this process that have not been claimed by a listener
+	activatedErr  error
+	activatedOnce sync.Once
+	activatedMu   sync.Mutex // protects activated
+)
+
+// load - reads the sockets passed to this process exactly once, as the environment is cleared after reading
+func load() (err error) {
+	activatedOnce.Do(func() {
+		activated, activatedErr = parseEnv()
+	})
+
+	return activatedErr
+}
+
+// parseEnv - parses the systemd socket activation environment variables LISTEN_PID, LISTEN_FDS and LISTEN_FDNAMES
+func parseEnv() (files map[string][]*os.File, err error) {
+
+	files = make(map[string][]*os.File)
+
+	pid := os.Getenv("LISTEN_PID")
+	fds := os.Getenv("LISTEN_FDS")
+	names := os.Getenv("LISTEN_FDNAMES")
+
+	// the readiness pipe must not leak into processes spawned by this one either
+	if fd, e := strconv.Atoi(os.Getenv(readyEnv)); nil == e {
+		closeOnExec(fd)
+	}
+
+	// unset the variables so they are not inherited by any child process this process spawns
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+
+	fdNames, err := parseFdNames(pid, fds, names, os.Getpid())
+	if nil != err {
+		return nil, err
+	}
+
+	for i, name := range fdNames {
+		fd := listenFdsStart + i
+		closeOnExec(fd)
+
+		files[name] = append(files[name], os.NewFile(uintptr(fd), name))
+	}
+
+	return
+}
+
+// parseFdNames - returns the name of every socket passed to the process with the given pid, in the order of their descriptors starting at 3.
+// Sockets without a name in LISTEN_FDNAMES are named `UnknownName`, no sockets are returned when they are meant for a different process
+func parseFdNames(pid, fds, names string, ownPid int) (fdNames []string, err error) {
+
+	if len(fds) == 0 {
+		return // no sockets were passed to this process
+	}
+
+	if len(pid) > 0 && pid != strconv.Itoa(ownPid) {
+		return // sockets are meant for a different process
+	}
+
+	count, err := strconv.Atoi(fds)
+	if nil != err || count < 0 {
+		return nil, fmt.Errorf("socket activation: invalid LISTEN_FDS '%s'", fds)
+	}
+
+	var nameList []string
+	if len(names) > 0 {
+		nameList = strings.Split(names, ":")
+	}
+
+	for i := 0; i < count; i++ {
+		name := UnknownName
+		if i < len(nameList) && len(nameList[i]) > 0 {
+			name = nameList[i]
+		}
+
+		fdNames = append(fdNames, name)
+	}
+
+	return
+}
+
+// take - removes and returns the first unclaimed socket with the given name, nil if there is none
+func take(name string) (f *os.File, err error) {
+
+	err = load()
+	if nil != err {
+		return nil, err
+	}
+
+	activatedMu.Lock()
+	defer activatedMu.Unlock()
+
+	files := activated[name]
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	f = files[0]
+	if len(files) == 1 {
+		delete(activated, name)
+	} else {
+		activated[name] = files[1:]
+	}
+
+	return
+}
+
+// Listener - returns the stream socket with the given name passed to this process through LISTEN_FDS, or nil if there is none.
+// Each socket can only be claimed once
+func Listener(name string) (ln net.Listener, err error) {
+
+	f, err := take(name)
+	if nil != err || nil == f {
+		return nil, err
+	}
+	defer f.Close() // the listener holds its own duplicate of the descriptor
+
+	ln, err = net.FileListener(f)
+	if nil != err {
+		return nil, fmt.Errorf("socket activation '%s': %w", name, err)
+	}
+
+	return
+}
+
+// PacketConn - returns the datagram socket with the given name passed to this process through LISTEN_FDS, or nil if there is none.
+// Each socket can only be claimed once
+func PacketConn(name string) (pc net.PacketConn, err error) {
+
+	f, err := take(name)
+	if nil != err || nil == f {
+		return nil, err
+	}
+	defer f.Close() // the connection holds its own duplicate of the descriptor
+
+	pc, err = net.FilePacketConn(f)
+	if nil != err {
+		return nil, fmt.Errorf("socket activation '%s': %w", name, err)
+	}
+
+	return
+}
+
+// Names - returns the names of sockets passed to this process that have not been claimed by any listener
+func Names() (names []string, err error) {
+
+	err = load()
+	if nil != err {
+		return nil, err
+	}
+
+	activatedMu.Lock()
+	defer activatedMu.Unlock()
+
+	for name, files := range activated {
+		for range files {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return
+}
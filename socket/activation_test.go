@@ -0,0 +1,59 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package socket
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseFdNames(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		pid     string
+		fds     string
+		names   string
+		want    []string
+		wantErr bool
+	}{
+		{name: "no sockets passed", pid: "100", fds: "", want: nil},
+		{name: "named sockets", pid: "100", fds: "2", names: "http:https", want: []string{"http", "https"}},
+		{name: "without LISTEN_PID", fds: "1", names: "http", want: []string{"http"}},
+		{name: "without LISTEN_FDNAMES", pid: "100", fds: "2", want: []string{UnknownName, UnknownName}},
+		{name: "fewer names than sockets", pid: "100", fds: "3", names: "http", want: []string{"http", UnknownName, UnknownName}},
+		{name: "empty name", pid: "100", fds: "3", names: "http::mqtt", want: []string{"http", UnknownName, "mqtt"}},
+		{name: "more names than sockets", pid: "100", fds: "1", names: "http:https", want: []string{"http"}},
+		{name: "same name twice", pid: "100", fds: "2", names: "http:http", want: []string{"http", "http"}},
+		{name: "zero sockets", pid: "100", fds: "0", want: nil},
+		{name: "meant for a different process", pid: "101", fds: "2", names: "http:https", want: nil},
+		{name: "invalid LISTEN_FDS", pid: "100", fds: "two", wantErr: true},
+		{name: "negative LISTEN_FDS", pid: "100", fds: "-1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFdNames(tt.pid, tt.fds, tt.names, 100)
+			if (nil != err) != tt.wantErr {
+				t.Fatalf("parseFdNames() = %v, expected error %t", err, tt.wantErr)
+			}
+
+			if strings.Join(got, ":") != strings.Join(tt.want, ":") || len(got) != len(tt.want) {
+				t.Errorf("parseFdNames() = %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
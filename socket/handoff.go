@@ -0,0 +1,126 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package socket
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sync"
+)
+
+// filer - listeners backed by a file descriptor that can be passed to another process
+type filer interface {
+	File() (*os.File, error)
+}
+
+// trackedSocket - active socket and the name it is passed under
+type trackedSocket struct {
+	name string
+	sock any // net.Listener or net.PacketConn
+}
+
+var (
+	tracked   []trackedSocket
+	trackedMu sync.Mutex // protects tracked
+)
+
+// Track - registers an active listening socket under the given name so it can be passed to a new process during an upgrade.
+// Only the raw socket should be tracked, not a listener wrapping it
+func Track(name string, ln net.Listener) {
+	track(name, ln)
+}
+
+// TrackPacketConn - registers an active datagram socket under the given name so it can be passed to a new process during an upgrade
+func TrackPacketConn(name string, pc net.PacketConn) {
+	track(name, pc)
+}
+
+// Untrack - removes a listening socket that has been closed from the sockets to be passed during an upgrade
+func Untrack(ln net.Listener) {
+	untrack(ln)
+}
+
+// UntrackPacketConn - removes a datagram socket that has been closed from the sockets to be passed during an upgrade
+func UntrackPacketConn(pc net.PacketConn) {
+	untrack(pc)
+}
+
+// track - adds the socket to the sockets to be passed during an upgrade
+func track(name string, sock any) {
+	trackedMu.Lock()
+	defer trackedMu.Unlock()
+
+	tracked = append(tracked, trackedSocket{name: name, sock: sock})
+}
+
+// untrack - removes the socket from the sockets to be passed during an upgrade
+func untrack(sock any) {
+	trackedMu.Lock()
+	defer trackedMu.Unlock()
+
+	for i, t := range tracked {
+		if t.sock == sock {
+			tracked = append(tracked[:i], tracked[i+1:]...)
+			return
+		}
+	}
+}
+
+// trackedFiles - returns duplicates of the file descriptors of all tracked sockets, along with their names, in the order they were tracked.
+// Sockets without a file descriptor, such as in-memory listeners, are skipped and reported in skipped rather than failing the whole handoff
+func trackedFiles() (files []*os.File, names []string, skipped []error) {
+	trackedMu.Lock()
+	defer trackedMu.Unlock()
+
+	for _, t := range tracked {
+		fl, ok := t.sock.(filer)
+		if !ok {
+			skipped = append(skipped, fmt.Errorf("socket '%s': socket of type %T cannot be passed to another process", t.name, t.sock))
+			continue
+		}
+
+		f, err := fl.File()
+		if nil != err {
+			skipped = append(skipped, fmt.Errorf("socket '%s': %w", t.name, err))
+			continue
+		}
+
+		files = append(files, f)
+		names = append(names, t.name)
+	}
+
+	return
+}
+
+// releaseTracked - stops closing tracked Unix sockets from removing their path, as the socket is now served by another process
+func releaseTracked() {
+	trackedMu.Lock()
+	defer trackedMu.Unlock()
+
+	for _, t := range tracked {
+		if ul, ok := t.sock.(*net.UnixListener); ok {
+			ul.SetUnlinkOnClose(false)
+		}
+	}
+}
+
+// closeFiles - closes all given files, ignoring errors
+func closeFiles(files []*os.File) {
+	for _, f := range files {
+		f.Close()
+	}
+}
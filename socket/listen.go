@@ -0,0 +1,32 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package socket
+
+import (
+	"net"
+)
+
+// ListenFunc - binds a listening socket, with the same signature as `net.Listen`. Set it in the configuration of a listener to replace
+// how the listener binds, e.g. with an in-memory listener so tests never open real sockets
+type ListenFunc func(network, address string) (ln net.Listener, err error)
+
+// FromListener - returns a ListenFunc serving on the given listener whatever the network and address, for listeners created elsewhere.
+// A listener cannot be used again once it is closed, so the listener can only be started once
+func FromListener(ln net.Listener) (fn ListenFunc) {
+	return func(network, address string) (net.Listener, error) {
+		return ln, nil
+	}
+}
@@ -0,0 +1,33 @@
+//go:build !unix
+
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package socket
+
+import (
+	"context"
+	"errors"
+)
+
+// Upgrade - passing sockets to a new process is not supported on this platform
+func Upgrade(ctx context.Context) (err error) {
+	return errors.New("socket upgrade: not supported on this platform")
+}
+
+// Ready - nothing to report as this process can never be started by an upgrade on this platform
+func Ready() (err error) {
+	return
+}
@@ -0,0 +1,160 @@
+//go:build unix
+
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package socket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var readyOnce sync.Once
+
+// Upgrade - starts a new instance of the running executable with the same arguments, passing it all tracked sockets through LISTEN_FDS.
+// It returns once the new process reports it is ready, after which this process should drain its listeners and exit.
+// If the new process exits or ctx expires before it is ready, the new process is killed and an error is returned.
+// Sockets without a file descriptor, such as in-memory listeners, are skipped and logged with `slog.Default()` rather than failing the upgrade
+func Upgrade(ctx context.Context) (err error) {
+
+	exe, err := os.Executable()
+	if nil != err {
+		return fmt.Errorf("socket upgrade: %w", err)
+	}
+
+	files, names, skipped := trackedFiles()
+	defer closeFiles(files)
+
+	if len(files) == 0 {
+		if len(skipped) > 0 {
+			return fmt.Errorf("socket upgrade: no socket can be passed to the new process: %w", errors.Join(skipped...))
+		}
+
+		return errors.New("socket upgrade: no active sockets to pass to the new process")
+	}
+
+	// the new process binds its own socket for every socket that cannot be passed, the other sockets are still handed over
+	for _, e := range skipped {
+		slog.Default().Warn("socket upgrade: socket not passed to the new process", "error", e)
+	}
+
+	// the new process writes to this pipe once all its listeners are ready
+	r, w, err := os.Pipe()
+	if nil != err {
+		return fmt.Errorf("socket upgrade: %w", err)
+	}
+	defer r.Close()
+
+	cmd := exec.Command(exe, os.Args[1:]...)
+	cmd.Env = upgradeEnv(os.Environ(), names)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = append(files, w)
+
+	err = cmd.Start()
+	w.Close() // only the new process should hold the write end, so a read returns EOF if it exits
+	if nil != err {
+		return fmt.Errorf("socket upgrade: %w", err)
+	}
+
+	ready := make(chan error, 1)
+	go func() {
+		buf := make([]byte, 1)
+		if _, e := r.Read(buf); nil != e {
+			ready <- fmt.Errorf("new process exited before becoming ready: %w", e)
+			return
+		}
+		ready <- nil
+	}()
+
+	select {
+	case err = <-ready:
+	case <-ctx.Done():
+		err = fmt.Errorf("new process did not become ready: %w", ctx.Err())
+	}
+
+	if nil != err {
+		cmd.Process.Kill()
+		go cmd.Wait() // reap the killed process
+		return fmt.Errorf("socket upgrade: %w", err)
+	}
+
+	// the new process outlives this one, release it without waiting
+	cmd.Process.Release()
+
+	// Unix socket paths now belong to the new process, draining this one must not remove them
+	releaseTracked()
+
+	return
+}
+
+// upgradeEnv - returns the environment of the new process, passing the sockets with the given names as LISTEN_FDS starting at descriptor 3,
+// followed by the readiness pipe
+func upgradeEnv(environ []string, names []string) (env []string) {
+
+	env = make([]string, 0, len(environ)+3)
+	for _, e := range environ {
+		if strings.HasPrefix(e, "LISTEN_") || strings.HasPrefix(e, readyEnv+"=") {
+			continue // never pass stale activation details
+		}
+		env = append(env, e)
+	}
+
+	env = append(env,
+		"LISTEN_FDS="+strconv.Itoa(len(names)),
+		"LISTEN_FDNAMES="+strings.Join(names, ":"),
+		readyEnv+"="+strconv.Itoa(listenFdsStart+len(names)), // pipe is passed right after the sockets
+	)
+
+	return
+}
+
+// Ready - informs the process that started this one through `Upgrade` that all listeners are ready, so it can drain and exit.
+// It does nothing if this process was not started by an upgrade
+func Ready() (err error) {
+
+	readyOnce.Do(func() {
+		v := os.Getenv(readyEnv)
+		os.Unsetenv(readyEnv)
+
+		if len(v) == 0 {
+			return
+		}
+
+		fd, e := strconv.Atoi(v)
+		if nil != e {
+			err = fmt.Errorf("socket ready: invalid %s '%s'", readyEnv, v)
+			return
+		}
+
+		f := os.NewFile(uintptr(fd), "upgrade-ready")
+		defer f.Close()
+
+		if _, e = f.Write([]byte{1}); nil != e {
+			err = fmt.Errorf("socket ready: %w", e)
+		}
+	})
+
+	return
+}
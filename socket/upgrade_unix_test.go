@@ -0,0 +1,147 @@
+//go:build unix
+
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package socket
+
+import (
+	"net"
+	"strings"
+	"testing"
+)
+
+// memoryListener - listener without a file descriptor, such as an in-memory listener
+type memoryListener struct {
+	net.Listener
+}
+
+func TestUpgradeEnv(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		environ []string
+		names   []string
+		want    []string
+	}{
+		{
+			name:    "sockets follow the standard descriptors, pipe follows the sockets",
+			environ: []string{"HOME=/root", "PATH=/bin"},
+			names:   []string{"REST", "TCP"},
+			want:    []string{"HOME=/root", "PATH=/bin", "LISTEN_FDS=2", "LISTEN_FDNAMES=REST:TCP", readyEnv + "=5"},
+		},
+		{
+			name:    "stale activation details are replaced",
+			environ: []string{"LISTEN_PID=42", "LISTEN_FDS=4", "LISTEN_FDNAMES=a:b:c:d", readyEnv + "=9", "HOME=/root"},
+			names:   []string{"MQTT"},
+			want:    []string{"HOME=/root", "LISTEN_FDS=1", "LISTEN_FDNAMES=MQTT", readyEnv + "=4"},
+		},
+		{
+			name:    "variables sharing a prefix with the ready variable are kept",
+			environ: []string{readyEnv + "_X=1"},
+			names:   []string{"REST"},
+			want:    []string{readyEnv + "_X=1", "LISTEN_FDS=1", "LISTEN_FDNAMES=REST", readyEnv + "=4"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := upgradeEnv(tt.environ, tt.names)
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("upgradeEnv() = %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrackedFiles(t *testing.T) {
+
+	listen := func(t *testing.T) net.Listener {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if nil != err {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { ln.Close() })
+
+		return ln
+	}
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if nil != err {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+
+	closed := listen(t)
+	closed.Close()
+
+	first, second := listen(t), listen(t)
+
+	Track("first", first)
+	Track("memory", memoryListener{Listener: first})
+	TrackPacketConn("udp", pc)
+	Track("closed", closed)
+	Track("second", second)
+
+	defer func() {
+		Untrack(first)
+		Untrack(memoryListener{Listener: first})
+		UntrackPacketConn(pc)
+		Untrack(closed)
+		Untrack(second)
+	}()
+
+	files, names, skipped := trackedFiles()
+	defer closeFiles(files)
+
+	// the descriptor of each socket given to the new process matches its position in LISTEN_FDNAMES
+	if want := "first:udp:second"; strings.Join(names, ":") != want {
+		t.Fatalf("names = %q, expected %s", names, want)
+	}
+
+	for i, f := range files {
+		var addr, want net.Addr
+
+		if names[i] == "udp" {
+			dup, err := net.FilePacketConn(f)
+			if nil != err {
+				t.Fatal(err)
+			}
+			addr, want = dup.LocalAddr(), pc.LocalAddr()
+			dup.Close()
+		} else {
+			dup, err := net.FileListener(f)
+			if nil != err {
+				t.Fatal(err)
+			}
+			addr, want = dup.Addr(), map[string]net.Addr{"first": first.Addr(), "second": second.Addr()}[names[i]]
+			dup.Close()
+		}
+
+		if addr.String() != want.String() {
+			t.Errorf("descriptor %d is bound to %s, expected %s", listenFdsStart+i, addr, want)
+		}
+	}
+
+	if len(skipped) != 2 {
+		t.Fatalf("skipped = %v, expected the in-memory and the closed listener", skipped)
+	}
+
+	for i, name := range []string{"memory", "closed"} {
+		if !strings.Contains(skipped[i].Error(), "'"+name+"'") {
+			t.Errorf("skipped[%d] = %v, expected socket %s", i, skipped[i], name)
+		}
+	}
+}
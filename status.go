@@ -0,0 +1,108 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package listener
+
+import (
+	"sync"
+
+	"github.com/handletec/listener/lifecycle"
+)
+
+// State - stage of the lifecycle a listener is in
+type State = lifecycle.State
+
+// Status - state of a listener at a point in time, along with the last error it reported
+type Status = lifecycle.Status
+
+const (
+	StateCreated     = lifecycle.Created     // listener was created but not initialized
+	StateInitialized = lifecycle.Initialized // listener was initialized and is ready to be started
+	StateStarting    = lifecycle.Starting    // listener is starting but is not yet accepting connections
+	StateRunning     = lifecycle.Running     // listener is bound and accepting connections
+	StateDraining    = lifecycle.Draining    // listener stopped accepting connections and is waiting for in-flight requests to complete
+	StateStopped     = lifecycle.Stopped     // listener stopped cleanly
+	StateFailed      = lifecycle.Failed      // listener stopped because of an error
+)
+
+// Status - returns the status of every listener, in the order they were added
+func (ls Listeners) Status() (statuses []Status) {
+
+	statuses = make([]Status, 0, len(ls))
+	for _, l := range ls {
+		statuses = append(statuses, l.Status())
+	}
+
+	return
+}
+
+// Running - determines if every listener is bound and accepting connections
+func (ls Listeners) Running() (running bool) {
+
+	for _, l := range ls {
+		if l.Status().State != StateRunning {
+			return false
+		}
+	}
+
+	return len(ls) > 0
+}
+
+// Subscribe - returns a single channel receiving the status of any listener after every state transition, and a function to stop receiving them which closes the channel.
+// Use `Status.Name` to tell the listeners apart
+func (ls Listeners) Subscribe(buffer int) (ch <-chan Status, cancel func()) {
+
+	if buffer < 1 {
+		buffer = 1
+	}
+
+	out := make(chan Status, buffer*len(ls)+1)
+	done := make(chan struct{})
+
+	var wg sync.WaitGroup
+	cancels := make([]func(), 0, len(ls))
+
+	for _, l := range ls {
+		sub, unsub := l.Subscribe(buffer)
+		cancels = append(cancels, unsub)
+
+		wg.Add(1)
+		go func(sub <-chan Status) {
+			defer wg.Done()
+
+			for status := range sub {
+				select {
+				case out <- status:
+				case <-done:
+				}
+			}
+		}(sub)
+	}
+
+	var once sync.Once
+	cancel = func() {
+		once.Do(func() {
+			close(done)
+			for _, unsub := range cancels {
+				unsub() // closes each listener channel, ending the goroutines forwarding them
+			}
+
+			wg.Wait()
+			close(out)
+		})
+	}
+
+	return out, cancel
+}
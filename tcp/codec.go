@@ -0,0 +1,242 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package tcp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrFrameTooLarge - returned when a frame exceeds the maximum size allowed by the codec
+var ErrFrameTooLarge = errors.New("frame exceeds maximum size")
+
+// Codec - splits a byte stream into frames and encodes frames to be written back
+type Codec interface {
+	Decode(r *bufio.Reader) (frame []byte, err error) // reads exactly one frame, returning io.EOF when the stream ends cleanly between frames
+	Encode(w io.Writer, frame []byte) (err error)     // writes a single frame
+}
+
+// LengthPrefixCodec - frames prefixed with their length as an unsigned integer
+type LengthPrefixCodec struct {
+	size     int              // number of bytes used by the length prefix, 1, 2, 4 or 8
+	order    binary.ByteOrder // byte order of the length prefix
+	maxFrame int              // largest frame accepted, excluding the prefix
+}
+
+// NewLengthPrefixCodec - create new instance of a length prefixed codec, size is the number of bytes used by the prefix (1, 2, 4 or 8)
+func NewLengthPrefixCodec(size int, order binary.ByteOrder, maxFrame int) (codec *LengthPrefixCodec, err error) {
+
+	switch size {
+	case 1, 2, 4, 8:
+	default:
+		return nil, fmt.Errorf("length prefix codec: invalid prefix size %d, must be 1, 2, 4 or 8", size)
+	}
+
+	if nil == order {
+		order = binary.BigEndian // network byte order
+	}
+
+	if maxFrame <= 0 {
+		return nil, errors.New("length prefix codec: maximum frame size must be greater than 0")
+	}
+
+	codec = new(LengthPrefixCodec)
+	codec.size = size
+	codec.order = order
+	codec.maxFrame = maxFrame
+
+	return
+}
+
+// Decode - reads the length prefix followed by the frame
+func (codec *LengthPrefixCodec) Decode(r *bufio.Reader) (frame []byte, err error) {
+
+	prefix := make([]byte, codec.size)
+	if _, err = io.ReadFull(r, prefix); nil != err {
+		return nil, err
+	}
+
+	var length uint64
+	switch codec.size {
+	case 1:
+		length = uint64(prefix[0])
+	case 2:
+		length = uint64(codec.order.Uint16(prefix))
+	case 4:
+		length = uint64(codec.order.Uint32(prefix))
+	case 8:
+		length = codec.order.Uint64(prefix)
+	}
+
+	if length > uint64(codec.maxFrame) {
+		return nil, fmt.Errorf("%w: %d bytes", ErrFrameTooLarge, length)
+	}
+
+	frame = make([]byte, length)
+	if _, err = io.ReadFull(r, frame); nil != err {
+		return nil, unexpectedEOF(err)
+	}
+
+	return
+}
+
+// Encode - writes the length prefix followed by the frame
+func (codec *LengthPrefixCodec) Encode(w io.Writer, frame []byte) (err error) {
+
+	length := uint64(len(frame))
+	if length > uint64(codec.maxFrame) || (codec.size < 8 && length >= 1<<(8*codec.size)) {
+		return fmt.Errorf("%w: %d bytes", ErrFrameTooLarge, length)
+	}
+
+	buf := make([]byte, codec.size, codec.size+len(frame))
+	switch codec.size {
+	case 1:
+		buf[0] = byte(length)
+	case 2:
+		codec.order.PutUint16(buf, uint16(length))
+	case 4:
+		codec.order.PutUint32(buf, uint32(length))
+	case 8:
+		codec.order.PutUint64(buf, length)
+	}
+
+	_, err = w.Write(append(buf, frame...))
+	return
+}
+
+// DelimiterCodec - frames terminated by a delimiter, such as a newline
+type DelimiterCodec struct {
+	delim    []byte
+	maxFrame int // largest frame accepted, excluding the delimiter
+}
+
+// NewDelimiterCodec - create new instance of a delimiter based codec, the delimiter is stripped from decoded frames and appended to encoded frames
+func NewDelimiterCodec(delim []byte, maxFrame int) (codec *DelimiterCodec, err error) {
+
+	if len(delim) == 0 {
+		return nil, errors.New("delimiter codec: delimiter cannot be left blank")
+	}
+
+	if maxFrame <= 0 {
+		return nil, errors.New("delimiter codec: maximum frame size must be greater than 0")
+	}
+
+	codec = new(DelimiterCodec)
+	codec.delim = append([]byte(nil), delim...)
+	codec.maxFrame = maxFrame
+
+	return
+}
+
+// Decode - reads until the delimiter is found
+func (codec *DelimiterCodec) Decode(r *bufio.Reader) (frame []byte, err error) {
+
+	last := codec.delim[len(codec.delim)-1]
+
+	for {
+		chunk, err := r.ReadSlice(last)
+		if len(frame)+len(chunk) > codec.maxFrame+len(codec.delim) {
+			return nil, fmt.Errorf("%w: no delimiter within %d bytes", ErrFrameTooLarge, codec.maxFrame)
+		}
+
+		frame = append(frame, chunk...)
+
+		switch {
+		case nil == err:
+			if bytes.HasSuffix(frame, codec.delim) {
+				return frame[:len(frame)-len(codec.delim)], nil
+			}
+			// matched only the last byte of a multi byte delimiter, keep reading
+		case errors.Is(err, bufio.ErrBufferFull):
+			// frame is longer than the read buffer, keep reading
+		case errors.Is(err, io.EOF) && len(frame) > 0:
+			return nil, io.ErrUnexpectedEOF
+		default:
+			return nil, err
+		}
+	}
+}
+
+// Encode - writes the frame followed by the delimiter
+func (codec *DelimiterCodec) Encode(w io.Writer, frame []byte) (err error) {
+
+	if len(frame) > codec.maxFrame {
+		return fmt.Errorf("%w: %d bytes", ErrFrameTooLarge, len(frame))
+	}
+
+	if bytes.Contains(frame, codec.delim) {
+		return errors.New("delimiter codec: frame contains the delimiter")
+	}
+
+	buf := make([]byte, 0, len(frame)+len(codec.delim))
+	buf = append(buf, frame...)
+	buf = append(buf, codec.delim...)
+
+	_, err = w.Write(buf)
+	return
+}
+
+// FixedCodec - frames that are always the same size
+type FixedCodec struct {
+	size int
+}
+
+// NewFixedCodec - create new instance of a fixed size codec
+func NewFixedCodec(size int) (codec *FixedCodec, err error) {
+
+	if size <= 0 {
+		return nil, errors.New("fixed codec: frame size must be greater than 0")
+	}
+
+	codec = new(FixedCodec)
+	codec.size = size
+
+	return
+}
+
+// Decode - reads exactly one frame of the configured size
+func (codec *FixedCodec) Decode(r *bufio.Reader) (frame []byte, err error) {
+
+	frame = make([]byte, codec.size)
+	if _, err = io.ReadFull(r, frame); nil != err {
+		return nil, err
+	}
+
+	return
+}
+
+// Encode - writes the frame, which must be exactly the configured size
+func (codec *FixedCodec) Encode(w io.Writer, frame []byte) (err error) {
+
+	if len(frame) != codec.size {
+		return fmt.Errorf("fixed codec: frame must be %d bytes, received %d", codec.size, len(frame))
+	}
+
+	_, err = w.Write(frame)
+	return
+}
+
+// unexpectedEOF - a stream ending in the middle of a frame is never a clean end of stream
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
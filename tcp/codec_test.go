@@ -0,0 +1,253 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package tcp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// decodeAll - decodes every frame of the stream, returning the error that stopped decoding
+func decodeAll(codec Codec, stream []byte, bufSize int) (frames []string, err error) {
+
+	r := bufio.NewReaderSize(bytes.NewReader(stream), bufSize)
+
+	for {
+		frame, err := codec.Decode(r)
+		if nil != err {
+			return frames, err
+		}
+
+		frames = append(frames, string(frame))
+	}
+}
+
+func TestLengthPrefixCodec(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		size       int
+		order      binary.ByteOrder
+		maxFrame   int
+		stream     []byte
+		wantFrames []string
+		wantErr    error
+	}{
+		{name: "one byte prefix", size: 1, maxFrame: 16, stream: []byte("\x02hi\x00\x03abc"), wantFrames: []string{"hi", "", "abc"}, wantErr: io.EOF},
+		{name: "two byte big endian", size: 2, order: binary.BigEndian, maxFrame: 16, stream: []byte("\x00\x02hi"), wantFrames: []string{"hi"}, wantErr: io.EOF},
+		{name: "two byte little endian", size: 2, order: binary.LittleEndian, maxFrame: 16, stream: []byte("\x02\x00hi"), wantFrames: []string{"hi"}, wantErr: io.EOF},
+		{name: "four byte prefix", size: 4, maxFrame: 16, stream: []byte("\x00\x00\x00\x02hi"), wantFrames: []string{"hi"}, wantErr: io.EOF},
+		{name: "eight byte prefix", size: 8, maxFrame: 16, stream: []byte("\x00\x00\x00\x00\x00\x00\x00\x02hi"), wantFrames: []string{"hi"}, wantErr: io.EOF},
+		{name: "frame of the maximum size", size: 1, maxFrame: 3, stream: []byte("\x03abc"), wantFrames: []string{"abc"}, wantErr: io.EOF},
+		{name: "oversize frame", size: 1, maxFrame: 3, stream: []byte("\x04abcd"), wantErr: ErrFrameTooLarge},
+		{name: "oversize eight byte prefix", size: 8, maxFrame: 16, stream: []byte("\xff\xff\xff\xff\xff\xff\xff\xff"), wantErr: ErrFrameTooLarge},
+		{name: "truncated prefix", size: 4, maxFrame: 16, stream: []byte("\x00\x00"), wantErr: io.ErrUnexpectedEOF},
+		{name: "truncated frame", size: 1, maxFrame: 16, stream: []byte("\x05ab"), wantErr: io.ErrUnexpectedEOF},
+		{name: "empty stream", size: 2, maxFrame: 16, wantErr: io.EOF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codec, err := NewLengthPrefixCodec(tt.size, tt.order, tt.maxFrame)
+			if nil != err {
+				t.Fatal(err)
+			}
+
+			frames, err := decodeAll(codec, tt.stream, 16)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Decode() = %v, want %v", err, tt.wantErr)
+			}
+
+			if strings.Join(frames, "|") != strings.Join(tt.wantFrames, "|") || len(frames) != len(tt.wantFrames) {
+				t.Errorf("frames = %q, want %q", frames, tt.wantFrames)
+			}
+		})
+	}
+}
+
+func TestLengthPrefixCodecEncode(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		size     int
+		maxFrame int
+		frame    []byte
+		want     []byte
+		wantErr  error
+	}{
+		{name: "two byte prefix", size: 2, maxFrame: 16, frame: []byte("hi"), want: []byte("\x00\x02hi")},
+		{name: "empty frame", size: 1, maxFrame: 16, frame: nil, want: []byte("\x00")},
+		{name: "larger than the maximum", size: 2, maxFrame: 1, frame: []byte("hi"), wantErr: ErrFrameTooLarge},
+		{name: "larger than the prefix allows", size: 1, maxFrame: 1024, frame: make([]byte, 256), wantErr: ErrFrameTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codec, err := NewLengthPrefixCodec(tt.size, nil, tt.maxFrame)
+			if nil != err {
+				t.Fatal(err)
+			}
+
+			var buf bytes.Buffer
+			err = codec.Encode(&buf, tt.frame)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Encode() = %v, want %v", err, tt.wantErr)
+			}
+
+			if nil == tt.wantErr && !bytes.Equal(buf.Bytes(), tt.want) {
+				t.Errorf("Encode() wrote % x, want % x", buf.Bytes(), tt.want)
+			}
+		})
+	}
+}
+
+func TestNewLengthPrefixCodec(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		size     int
+		maxFrame int
+		wantErr  bool
+	}{
+		{name: "valid", size: 4, maxFrame: 1024},
+		{name: "invalid prefix size", size: 3, maxFrame: 1024, wantErr: true},
+		{name: "no maximum frame size", size: 2, maxFrame: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewLengthPrefixCodec(tt.size, nil, tt.maxFrame); (nil != err) != tt.wantErr {
+				t.Errorf("NewLengthPrefixCodec() = %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDelimiterCodec(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		delim      string
+		maxFrame   int
+		bufSize    int
+		stream     string
+		wantFrames []string
+		wantErr    error
+	}{
+		{name: "newline", delim: "\n", maxFrame: 16, stream: "one\ntwo\n\nthree\n", wantFrames: []string{"one", "two", "", "three"}, wantErr: io.EOF},
+		{name: "multi byte delimiter", delim: "\r\n", maxFrame: 16, stream: "one\r\ntwo\nstill two\r\n", wantFrames: []string{"one", "two\nstill two"}, wantErr: io.EOF},
+		{name: "frame longer than the read buffer", delim: "\n", maxFrame: 64, bufSize: 16, stream: strings.Repeat("a", 40) + "\n", wantFrames: []string{strings.Repeat("a", 40)}, wantErr: io.EOF},
+		{name: "delimiter split across reads", delim: "\r\n", maxFrame: 64, bufSize: 16, stream: strings.Repeat("a", 15) + "\r\nb\r\n", wantFrames: []string{strings.Repeat("a", 15), "b"}, wantErr: io.EOF},
+		{name: "frame of the maximum size", delim: "\n", maxFrame: 3, stream: "abc\n", wantFrames: []string{"abc"}, wantErr: io.EOF},
+		{name: "oversize frame", delim: "\n", maxFrame: 3, stream: "abcd\n", wantErr: ErrFrameTooLarge},
+		{name: "no delimiter within the maximum", delim: "\n", maxFrame: 8, bufSize: 16, stream: strings.Repeat("a", 40), wantErr: ErrFrameTooLarge},
+		{name: "truncated frame", delim: "\n", maxFrame: 16, stream: "one\ntw", wantFrames: []string{"one"}, wantErr: io.ErrUnexpectedEOF},
+		{name: "empty stream", delim: "\n", maxFrame: 16, wantErr: io.EOF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codec, err := NewDelimiterCodec([]byte(tt.delim), tt.maxFrame)
+			if nil != err {
+				t.Fatal(err)
+			}
+
+			bufSize := tt.bufSize
+			if bufSize == 0 {
+				bufSize = 4096
+			}
+
+			frames, err := decodeAll(codec, []byte(tt.stream), bufSize)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Decode() = %v, want %v", err, tt.wantErr)
+			}
+
+			if strings.Join(frames, "|") != strings.Join(tt.wantFrames, "|") || len(frames) != len(tt.wantFrames) {
+				t.Errorf("frames = %q, want %q", frames, tt.wantFrames)
+			}
+		})
+	}
+}
+
+func TestDelimiterCodecEncode(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		frame   string
+		want    string
+		wantErr bool
+	}{
+		{name: "frame", frame: "hello", want: "hello\r\n"},
+		{name: "empty frame", frame: "", want: "\r\n"},
+		{name: "larger than the maximum", frame: "hello world", wantErr: true},
+		{name: "contains the delimiter", frame: "a\r\nb", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codec, err := NewDelimiterCodec([]byte("\r\n"), 8)
+			if nil != err {
+				t.Fatal(err)
+			}
+
+			var buf bytes.Buffer
+			if err = codec.Encode(&buf, []byte(tt.frame)); (nil != err) != tt.wantErr {
+				t.Fatalf("Encode() = %v, want error %t", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && buf.String() != tt.want {
+				t.Errorf("Encode() wrote %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestFixedCodec(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		stream     string
+		wantFrames []string
+		wantErr    error
+	}{
+		{name: "whole frames", stream: "abcdef", wantFrames: []string{"abc", "def"}, wantErr: io.EOF},
+		{name: "truncated frame", stream: "abcde", wantFrames: []string{"abc"}, wantErr: io.ErrUnexpectedEOF},
+		{name: "empty stream", wantErr: io.EOF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codec, err := NewFixedCodec(3)
+			if nil != err {
+				t.Fatal(err)
+			}
+
+			frames, err := decodeAll(codec, []byte(tt.stream), 16)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Decode() = %v, want %v", err, tt.wantErr)
+			}
+
+			if strings.Join(frames, "|") != strings.Join(tt.wantFrames, "|") || len(frames) != len(tt.wantFrames) {
+				t.Errorf("frames = %q, want %q", frames, tt.wantFrames)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package tcp
+
+import (
+	"errors"
+	"time"
+
+	"github.com/handletec/listener/connlimit"
+	"github.com/handletec/listener/ipfilter"
+	"github.com/handletec/listener/proxyproto"
+	"github.com/handletec/listener/socket"
+)
+
+// HandlerFunc - function run for every frame decoded from a connection, returning an error closes the connection
+type HandlerFunc func(conn *Conn, frame []byte) (err error)
+
+// Middleware - wraps a handler to run common tasks such as authentication or logging before the handler is called
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// Config - listener specific configuration
+type Config struct {
+	Codec           Codec
+	ReadTimeout     time.Duration                // maximum time to wait for the next frame before closing the connection, 0 waits forever
+	WriteTimeout    time.Duration                // maximum time to write a single frame
+	ShutdownTimeout time.Duration                // maximum time to wait for connections to finish processing their current frame when the listener is stopped
+	OnConnect       func(conn *Conn) (err error) // (optional) run when a connection is accepted, returning an error closes the connection
+	OnDisconnect    func(conn *Conn, err error)  // (optional) run when a connection is closed, with the error that caused it if any
+	IPFilter        *ipfilter.Filter             // (optional) allow and deny lists of client addresses, checked as soon as connections are accepted, so against the load balancer with the PROXY protocol
+	ConnLimit       *connlimit.Config            // (optional) caps the open connections, in total and from each client IP address
+	ProxyProtocol   *proxyproto.Config           // (optional) read the address of the original client from the PROXY header sent by trusted load balancers
+	Listen          socket.ListenFunc            // (optional) binds the listener instead of `net.Listen`, e.g. an in-memory listener for tests
+	handler         HandlerFunc
+}
+
+// NewConfig - creates new instance of config
+func NewConfig() (cfg *Config) {
+	cfg = new(Config)
+
+	// set default configuration
+	cfg.ReadTimeout = time.Duration(5 * time.Minute)
+	cfg.WriteTimeout = time.Duration(10 * time.Second)
+	cfg.ShutdownTimeout = time.Duration(30 * time.Second)
+	cfg.Codec, _ = NewDelimiterCodec([]byte("\n"), 64*1024) // newline delimited frames up to 64 KiB
+	cfg.handler = nil                                       // default create a nil instance of handler for error checking
+
+	return
+}
+
+// SetCodec - sets the codec used to split the byte stream into frames
+func (cfg *Config) SetCodec(codec Codec) {
+	cfg.Codec = codec
+}
+
+// SetHandler - sets the function to run for every decoded frame, with optional middlewares
+func (cfg *Config) SetHandler(hFn HandlerFunc, middlewares ...Middleware) (err error) {
+
+	if nil == hFn {
+		return errors.New("TCP sethandler: handler function cannot be nil")
+	}
+
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		hFn = middlewares[i](hFn)
+	}
+
+	cfg.handler = hFn
+	return
+}
@@ -0,0 +1,121 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package tcp
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"net"
+	"sync"
+	"time"
+)
+
+// Conn - single client connection, passed to handlers along with every decoded frame
+type Conn struct {
+	conn         net.Conn
+	r            *bufio.Reader
+	codec        Codec
+	writeTimeout time.Duration
+	ctx          context.Context // per connection context, cancelled once the connection is closed
+	cancel       context.CancelFunc
+	closeOnce    sync.Once
+	ctxMu        sync.Mutex // protects ctx
+	writeMu      sync.Mutex // serializes writes
+}
+
+// newConn - create new instance of a connection
+func newConn(conn net.Conn, codec Codec, writeTimeout time.Duration) (c *Conn) {
+	c = new(Conn)
+	c.conn = conn
+	c.r = bufio.NewReader(conn)
+	c.codec = codec
+	c.writeTimeout = writeTimeout
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+
+	return
+}
+
+// Context - returns the context of this connection, which is cancelled once the connection is closed
+func (c *Conn) Context() (ctx context.Context) {
+	c.ctxMu.Lock()
+	defer c.ctxMu.Unlock()
+
+	return c.ctx
+}
+
+// WithValue - stores a value in the connection context, making it available to every subsequent frame of this connection
+func (c *Conn) WithValue(key, value any) {
+	c.ctxMu.Lock()
+	defer c.ctxMu.Unlock()
+
+	c.ctx = context.WithValue(c.ctx, key, value)
+}
+
+// Value - returns a value stored in the connection context
+func (c *Conn) Value(key any) (value any) {
+	return c.Context().Value(key)
+}
+
+// Write - encodes the frame with the listener codec and writes it to the client, safe to call from multiple goroutines
+func (c *Conn) Write(frame []byte) (err error) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if c.writeTimeout > 0 {
+		c.conn.SetWriteDeadline(time.Now().Add(c.writeTimeout))
+	}
+
+	return c.codec.Encode(c.conn, frame)
+}
+
+// RemoteAddr - returns the address of the client. Behind a load balancer sending the PROXY protocol, it is the original client and the call
+// waits for the header of the load balancer, so a connection that never sends it holds up only its own goroutine
+func (c *Conn) RemoteAddr() (addr net.Addr) {
+	return c.conn.RemoteAddr()
+}
+
+// LocalAddr - returns the address of the listener the client connected to
+func (c *Conn) LocalAddr() (addr net.Addr) {
+	return c.conn.LocalAddr()
+}
+
+// TLS - returns the TLS details of the connection including the client certificates, nil for connections without TLS.
+// The handshake is completed before `OnConnect` runs, so client certificates are always available
+func (c *Conn) TLS() (state *tls.ConnectionState) {
+	tlsConn, ok := c.conn.(*tls.Conn)
+	if !ok {
+		return nil
+	}
+
+	cs := tlsConn.ConnectionState()
+	return &cs
+}
+
+// NetConn - returns the underlying connection, e.g. to inspect the TLS connection state
+func (c *Conn) NetConn() (conn net.Conn) {
+	return c.conn
+}
+
+// Close - closes the connection, safe to call multiple times
+func (c *Conn) Close() (err error) {
+	c.closeOnce.Do(func() {
+		c.cancel()
+		err = c.conn.Close()
+	})
+
+	return
+}
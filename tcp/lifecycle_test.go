@@ -0,0 +1,113 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package tcp_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/handletec/listener/listenertest"
+	"github.com/handletec/listener/tcp"
+)
+
+func TestShutdown(t *testing.T) {
+
+	started, release := make(chan struct{}), make(chan struct{})
+
+	cfg := tcp.NewConfig()
+	if err := cfg.SetHandler(func(conn *tcp.Conn, frame []byte) (err error) {
+		if string(frame) == "slow" {
+			close(started)
+			<-release
+		}
+		return conn.Write(bytes.ToUpper(frame))
+	}); nil != err {
+		t.Fatal(err)
+	}
+
+	l := tcp.New()
+	if err := l.SetConfig(cfg); nil != err {
+		t.Fatal(err)
+	}
+
+	opts := listenertest.NewOptions()
+	opts.InMemory = true
+
+	s := listenertest.Start(t, l, opts)
+
+	idle, busy := s.Dial(), s.Dial()
+	idle.SetDeadline(time.Now().Add(5 * time.Second))
+	busy.SetDeadline(time.Now().Add(5 * time.Second))
+
+	idleReader, busyReader := bufio.NewReader(idle), bufio.NewReader(busy)
+
+	// the idle connection is served once, so it was accepted before shutting down
+	if _, err := idle.Write([]byte("hello\n")); nil != err {
+		t.Fatal(err)
+	}
+
+	if line, err := idleReader.ReadString('\n'); nil != err || line != "HELLO\n" {
+		t.Fatalf("response = %q, %v, expected %q", line, err, "HELLO\n")
+	}
+
+	if _, err := busy.Write([]byte("slow\n")); nil != err {
+		t.Fatal(err)
+	}
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- l.Shutdown(ctx)
+	}()
+
+	// connections waiting for their next frame are closed straight away
+	if _, err := idleReader.ReadByte(); !errors.Is(err, io.EOF) {
+		t.Errorf("idle connection still open after shutdown: %v", err)
+	}
+
+	// the frame being handled completes and its response is written before the connection is closed
+	select {
+	case err := <-stopped:
+		t.Fatalf("Shutdown() = %v while a frame was being handled, expected it to wait", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+
+	if line, err := busyReader.ReadString('\n'); nil != err || line != "SLOW\n" {
+		t.Errorf("response = %q, %v, expected %q", line, err, "SLOW\n")
+	}
+
+	if _, err := busyReader.ReadByte(); !errors.Is(err, io.EOF) {
+		t.Errorf("connection still open once its frame was handled: %v", err)
+	}
+
+	if err := <-stopped; nil != err {
+		t.Fatalf("Shutdown() = %v", err)
+	}
+
+	if err := l.Shutdown(ctx); nil != err {
+		t.Errorf("second Shutdown() = %v, expected nil", err)
+	}
+}
@@ -0,0 +1,471 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package tcp
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/handletec/listener/describe"
+	"github.com/handletec/listener/internal/accept"
+	"github.com/handletec/listener/lifecycle"
+	"github.com/handletec/listener/socket"
+	slogformatter "github.com/samber/slog-formatter"
+)
+
+const (
+	// DefaultAddr - listen on all IPv4 and IPv6 interfaces
+	DefaultAddr = "[::]"
+
+	// DefaultPort - default port to listen on
+	DefaultPort = 9000
+
+	// EphemeralPort - let the OS pick any available port, use `Addr` to find the port that was bound
+	EphemeralPort = -1
+)
+
+// Listener - implementation of raw TCP listener, passing decoded frames to the configured handler
+type Listener struct {
+	lifecycle.Lifecycle // state, hooks and readiness, reported through `Status`, `Subscribe` and `Ready`
+
+	name      string
+	address   string
+	port      int
+	tlsConfig *tls.Config
+	logger    *slog.Logger
+	config    *Config
+	ln        net.Listener
+	addr      net.Addr
+	conns     map[*Conn]struct{}
+	wg        sync.WaitGroup // tracks open connections
+	closing   atomic.Bool
+	mu        sync.Mutex // protects ln, addr and conns
+}
+
+// New - create new instance of the TCP listener
+func New() (l *Listener) {
+	l = new(Listener)
+	return
+}
+
+// Name - returns the name of this listener
+func (l *Listener) Name() (str string) {
+	if len(l.name) == 0 {
+		return "TCP"
+	}
+
+	return l.name
+}
+
+// SetName - sets a custom name for this listener, used in logs and to match sockets passed through socket activation
+func (l *Listener) SetName(name string) {
+	l.name = name
+}
+
+// Init - initializes this listener with any necessary configuration parameters
+func (l *Listener) Init(logger *slog.Logger, address string, port int, tlsConfig *tls.Config) (err error) {
+
+	defer func() {
+		if nil != err {
+			l.SetState(l.Name(), lifecycle.Failed, err)
+			return
+		}
+		l.SetState(l.Name(), lifecycle.Initialized, nil)
+	}()
+
+	if len(address) == 0 {
+		address = DefaultAddr // if no address is given we have it listen on all IPv4 and IPv6 interfaces
+	}
+
+	switch {
+	case port == 0:
+		port = DefaultPort // default port if none is given
+	case port == EphemeralPort:
+		port = 0 // the OS assigns a free port when binding to port 0
+	case port < 0 || port > 65535:
+		return fmt.Errorf("TCP init: invalid port %d", port)
+	}
+
+	l.address = address
+	l.port = port
+	l.tlsConfig = tlsConfig
+
+	l.mu.Lock()
+	l.addr = nil
+	l.mu.Unlock()
+
+	if nil == logger {
+		// if no logger is given, create a new instance
+		logger = slog.New(
+			slogformatter.NewFormatterHandler(
+				slogformatter.TimezoneConverter(time.UTC),
+				slogformatter.TimeFormatter(time.RFC3339, nil),
+			)(
+				slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{}),
+			),
+		)
+	}
+
+	l.logger = logger
+
+	if nil == l.config {
+		// if no configuration is set, init a new one with sane default values
+		l.config = NewConfig()
+	}
+
+	if err = l.Hooks().Run(context.Background(), lifecycle.EventInit); nil != err {
+		return fmt.Errorf("TCP init: %w", err)
+	}
+
+	return
+}
+
+// SetConfig - sets configuration details for this listener
+func (l *Listener) SetConfig(config any) (err error) {
+	cfg, ok := config.(*Config)
+	if !ok {
+		return fmt.Errorf("TCP setconfig: expected *tcp.Config, received %T", config)
+	}
+
+	l.config = cfg
+	return
+}
+
+// Config - returns the configuration in use by this listener
+func (l *Listener) Config() (config any) {
+	return l.config
+}
+
+// Start - starts this listener and blocks until it stops, cancelling ctx gracefully shuts down the listener
+func (l *Listener) Start(ctx context.Context) (err error) {
+	l.logger.Info("listener starting", "listener", l.Name())
+	l.SetState(l.Name(), lifecycle.Starting, nil)
+
+	defer func() {
+		if nil != err {
+			l.SetState(l.Name(), lifecycle.Failed, err)
+		}
+	}()
+
+	if nil == l.config.handler {
+		return errors.New("TCP start: no handler configured")
+	}
+
+	if nil == l.config.Codec {
+		return errors.New("TCP start: no codec configured")
+	}
+
+	chain := accept.Chain{IPFilter: l.config.IPFilter, ConnLimit: l.config.ConnLimit, ProxyProtocol: l.config.ProxyProtocol}
+	if err = chain.Validate(); nil != err {
+		return fmt.Errorf("start tcp: %w", err)
+	}
+
+	if err = l.Hooks().Run(ctx, lifecycle.EventBeforeStart); nil != err {
+		return fmt.Errorf("start tcp: %w", err)
+	}
+
+	ln, err := l.listen()
+	if nil != err {
+		return fmt.Errorf("start tcp: %w", err)
+	}
+
+	// allow the socket to be passed to a new process during a binary upgrade
+	socket.Track(l.Name(), ln)
+	defer socket.Untrack(ln)
+
+	tlsEnabled := l.tlsEnabled()
+	scheme := "tcp"
+	if tlsEnabled {
+		scheme = "tls"
+	}
+
+	if tlsEnabled {
+		chain.TLS = l.tlsConfig
+	}
+
+	ln = chain.Wrap(ln, l.logger.With("listener", l.Name()))
+
+	l.closing.Store(false)
+
+	l.mu.Lock()
+	l.ln = ln
+	l.addr = ln.Addr()
+	l.conns = make(map[*Conn]struct{})
+	l.mu.Unlock()
+
+	l.logger.Info("listener started", "listener", l.Name(), "address", scheme+"://"+ln.Addr().String(), "tls", strconv.FormatBool(tlsEnabled))
+
+	acceptErr := make(chan error, 1)
+	go func() {
+		acceptErr <- l.accept(ln)
+	}()
+
+	if err = l.Started(ctx, l.Name()); nil != err {
+		// a started hook failed, close the accept loop and the connections accepted meanwhile so no frames are handled half set up
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), l.config.ShutdownTimeout)
+		defer cancel()
+
+		l.Shutdown(shutdownCtx)
+		<-acceptErr
+
+		return fmt.Errorf("start tcp: %w", err)
+	}
+
+	select {
+	case err = <-acceptErr:
+		if nil != err {
+			// the accept loop failed, e.g. the socket was closed underneath it, the stopped hooks still run to release what the started hooks acquired
+			err = errors.Join(err, l.Hooks().Run(context.WithoutCancel(ctx), lifecycle.EventStopped))
+		}
+	case <-ctx.Done():
+		// the caller no longer wants this listener running, drain in-flight frames before returning
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), l.config.ShutdownTimeout)
+		defer cancel()
+
+		err = l.Shutdown(shutdownCtx)
+		<-acceptErr
+	}
+
+	if nil != err {
+		return fmt.Errorf("start tcp: %w", err)
+	}
+
+	return nil
+}
+
+// accept - accepts connections until the listener is closed
+func (l *Listener) accept(ln net.Listener) (err error) {
+
+	return accept.Serve(ln, l.logger.With("listener", l.Name()), l.isClosing, func(conn net.Conn) (ok bool) {
+
+		c := newConn(conn, l.config.Codec, l.config.WriteTimeout)
+
+		l.mu.Lock()
+		if l.isClosing() {
+			l.mu.Unlock()
+			conn.Close()
+			return false
+		}
+		l.conns[c] = struct{}{}
+		l.wg.Add(1)
+		l.mu.Unlock()
+
+		go func() {
+			defer l.wg.Done()
+			defer func() {
+				l.mu.Lock()
+				delete(l.conns, c)
+				l.mu.Unlock()
+			}()
+
+			l.serve(c)
+		}()
+
+		return true
+	})
+}
+
+// serve - decodes frames from the connection and passes them to the handler until the connection is closed
+func (l *Listener) serve(c *Conn) {
+
+	var err error
+
+	defer func() {
+		c.Close()
+
+		if nil != l.config.OnDisconnect {
+			l.config.OnDisconnect(c, err)
+		}
+	}()
+
+	// complete the TLS handshake up front rather than on the first read, so `OnConnect` can authenticate clients by their certificate
+	if tlsConn, ok := c.conn.(*tls.Conn); ok {
+		if l.config.ReadTimeout > 0 {
+			c.conn.SetDeadline(time.Now().Add(l.config.ReadTimeout))
+		}
+
+		if err = tlsConn.HandshakeContext(c.Context()); nil != err {
+			l.logger.Debug("TLS handshake failed", "listener", l.Name(), "remote", c.RemoteAddr().String(), "error", err)
+			return
+		}
+
+		c.conn.SetDeadline(time.Time{})
+	}
+
+	if nil != l.config.OnConnect {
+		if err = l.config.OnConnect(c); nil != err {
+			l.logger.Debug("connection rejected", "listener", l.Name(), "remote", c.RemoteAddr().String(), "error", err)
+			return
+		}
+	}
+
+	for {
+		if l.config.ReadTimeout > 0 {
+			c.conn.SetReadDeadline(time.Now().Add(l.config.ReadTimeout))
+		} else {
+			c.conn.SetReadDeadline(time.Time{})
+		}
+
+		if l.isClosing() {
+			return // listener is shutting down, do not start processing another frame
+		}
+
+		var frame []byte
+		frame, err = l.config.Codec.Decode(c.r)
+		if nil != err {
+			if errors.Is(err, io.EOF) || l.isClosing() {
+				err = nil // clean close by the client or the listener
+				return
+			}
+
+			l.logger.Debug("connection read failed", "listener", l.Name(), "remote", c.RemoteAddr().String(), "error", err)
+			return
+		}
+
+		if err = l.config.handler(c, frame); nil != err {
+			l.logger.Debug("connection closed by handler", "listener", l.Name(), "remote", c.RemoteAddr().String(), "error", err)
+			return
+		}
+	}
+}
+
+// listen - binds with the listen function of the configuration if there is one, otherwise uses the socket passed by the supervisor
+// under this listener's name if there is one, otherwise binds to the configured address and port
+func (l *Listener) listen() (ln net.Listener, err error) {
+
+	address := net.JoinHostPort(strings.Trim(l.address, "[]"), strconv.Itoa(l.port))
+
+	if nil != l.config.Listen {
+		return l.config.Listen("tcp", address)
+	}
+
+	ln, err = socket.Listener(l.Name())
+	if nil != err {
+		return nil, err
+	}
+
+	if nil != ln {
+		l.logger.Info("listener using activated socket", "listener", l.Name(), "address", ln.Addr().String())
+		return
+	}
+
+	return net.Listen("tcp", address)
+}
+
+// Shutdown - stops accepting new connections and waits for connections to finish processing their current frame or for ctx to expire, whichever comes first
+func (l *Listener) Shutdown(ctx context.Context) (err error) {
+
+	l.mu.Lock()
+	ln := l.ln
+	l.ln = nil
+	l.mu.Unlock()
+
+	if nil == ln {
+		return // listener was never started, nothing to shutdown
+	}
+
+	l.logger.Info("listener shutting down", "listener", l.Name())
+	l.SetState(l.Name(), lifecycle.Draining, nil)
+
+	// deregister from e.g. service discovery while connections are still accepted, so clients reconnect elsewhere
+	hookErr := l.Hooks().Run(ctx, lifecycle.EventShutdown)
+
+	l.closing.Store(true)
+	ln.Close()
+
+	// interrupt every read, including frames only partly received which are dropped, frames already decoded finish running their handler
+	l.mu.Lock()
+	conns := make([]*Conn, 0, len(l.conns))
+	for c := range l.conns {
+		conns = append(conns, c)
+		c.conn.SetReadDeadline(time.Now())
+	}
+	l.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		l.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		for _, c := range conns {
+			c.Close()
+		}
+		<-done
+		err = fmt.Errorf("shutdown tcp: %w", ctx.Err())
+	}
+
+	l.logger.Info("listener stopped", "listener", l.Name())
+
+	// connections finished their current frame or were closed, stopped hooks run either way
+	err = l.Stopped(ctx, l.Name(), errors.Join(err, hookErr))
+
+	return
+}
+
+// Describe - describes the address and TLS settings of this listener
+func (l *Listener) Describe() (d describe.Description) {
+	d.Name = l.Name()
+	d.Protocol = "TCP"
+	d.Address = describe.Address(l.Addr(), l.address, l.port)
+	d.TLS = describe.TLSOf(l.tlsConfig)
+
+	return
+}
+
+// Addr - returns the address this listener is bound to, or nil if it has not been started
+func (l *Listener) Addr() (addr net.Addr) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.addr
+}
+
+// Status - returns the lifecycle state of this TCP listener under its current name, which may have been changed by `SetName`
+func (l *Listener) Status() (status lifecycle.Status) {
+	status = l.Lifecycle.Status()
+	status.Name = l.Name()
+
+	return
+}
+
+// isClosing - determines if the listener is shutting down
+func (l *Listener) isClosing() (closing bool) {
+	return l.closing.Load()
+}
+
+// tlsEnabled - determines if the given TLS configuration is able to serve certificates
+func (l *Listener) tlsEnabled() (enabled bool) {
+	if nil == l.tlsConfig {
+		return false
+	}
+
+	return len(l.tlsConfig.Certificates) > 0 || nil != l.tlsConfig.GetCertificate || nil != l.tlsConfig.GetConfigForClient
+}
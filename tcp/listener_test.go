@@ -0,0 +1,96 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package tcp
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/handletec/listener/memnet"
+)
+
+// flakyListener - fails the first accepts as if the process ran out of file descriptors
+type flakyListener struct {
+	net.Listener
+	failures atomic.Int32
+}
+
+func (fl *flakyListener) Accept() (net.Conn, error) {
+	if fl.failures.Add(-1) >= 0 {
+		return nil, &net.OpError{Op: "accept", Net: "tcp", Err: os.NewSyscallError("accept", syscall.EMFILE)}
+	}
+
+	return fl.Listener.Accept()
+}
+
+func TestAcceptBacksOffOnTemporaryErrors(t *testing.T) {
+
+	mem := memnet.NewListener("")
+	flaky := &flakyListener{Listener: mem}
+	flaky.failures.Store(3)
+
+	cfg := NewConfig()
+	cfg.Listen = func(network, address string) (net.Listener, error) { return flaky, nil }
+	cfg.SetHandler(func(conn *Conn, frame []byte) (err error) {
+		return conn.Write(frame)
+	})
+
+	l := New()
+	l.SetConfig(cfg)
+	if err := l.Init(slog.New(slog.NewTextHandler(io.Discard, nil)), "127.0.0.1", EphemeralPort, nil); nil != err {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- l.Start(ctx)
+	}()
+	defer func() {
+		cancel()
+		if err := <-done; nil != err {
+			t.Errorf("start: %v", err)
+		}
+	}()
+
+	select {
+	case <-l.Ready():
+	case err := <-done:
+		t.Fatalf("start: %v", err)
+	}
+
+	dialCtx, dialCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer dialCancel()
+
+	conn, err := mem.DialContext(dialCtx, "memory", "")
+	if nil != err {
+		t.Fatalf("listener stopped accepting after EMFILE: %v", err)
+	}
+	defer conn.Close()
+
+	conn.Write([]byte("ping\n"))
+	if line, err := bufio.NewReader(conn).ReadString('\n'); nil != err || line != "ping\n" {
+		t.Fatalf("echo: %q, %v", line, err)
+	}
+}
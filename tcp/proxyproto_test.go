@@ -0,0 +1,167 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package tcp_test
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/handletec/listener/ipfilter"
+	"github.com/handletec/listener/listenertest"
+	"github.com/handletec/listener/proxyproto"
+	"github.com/handletec/listener/tcp"
+)
+
+func TestProxyProtocol(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		header     string
+		optional   bool
+		wantRemote string // prefix of the remote address reported to the handler
+		wantLocal  string // prefix of the local address reported to the handler
+	}{
+		{name: "addresses of the header", header: "PROXY TCP4 203.0.113.7 198.51.100.1 5000 443\r\n", wantRemote: "203.0.113.7:5000", wantLocal: "198.51.100.1:443"},
+		{name: "health check of the load balancer", header: "PROXY UNKNOWN\r\n", wantRemote: "127.0.0.1:", wantLocal: "127.0.0.1:"},
+		{name: "optional header left out", optional: true, wantRemote: "127.0.0.1:", wantLocal: "127.0.0.1:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pp, err := proxyproto.NewConfig("127.0.0.1")
+			if nil != err {
+				t.Fatal(err)
+			}
+			pp.HeaderTimeout = 10 * time.Second
+			pp.Optional = tt.optional
+
+			cfg := tcp.NewConfig()
+			cfg.ProxyProtocol = pp
+			cfg.OnConnect = func(conn *tcp.Conn) (err error) {
+				conn.RemoteAddr() // waits for the header, without holding up the other connections
+				return
+			}
+			if err = cfg.SetHandler(func(conn *tcp.Conn, frame []byte) (err error) {
+				return conn.Write([]byte(conn.RemoteAddr().String() + " " + conn.LocalAddr().String()))
+			}); nil != err {
+				t.Fatal(err)
+			}
+
+			l := tcp.New()
+			if err = l.SetConfig(cfg); nil != err {
+				t.Fatal(err)
+			}
+
+			s := listenertest.Start(t, l, nil)
+
+			// a trusted source that connects but never sends its header
+			silent, err := net.Dial("tcp", s.Addr().String())
+			if nil != err {
+				t.Fatal(err)
+			}
+			defer silent.Close()
+
+			conn, err := net.Dial("tcp", s.Addr().String())
+			if nil != err {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+
+			conn.SetDeadline(time.Now().Add(2 * time.Second))
+
+			if _, err = io.WriteString(conn, tt.header+"addr\n"); nil != err {
+				t.Fatal(err)
+			}
+
+			line, err := bufio.NewReader(conn).ReadString('\n')
+			if nil != err {
+				t.Fatalf("client not served while another one is silent: %v", err)
+			}
+
+			remote, local, _ := strings.Cut(strings.TrimSuffix(line, "\n"), " ")
+			if !strings.HasPrefix(remote, tt.wantRemote) || !strings.HasPrefix(local, tt.wantLocal) {
+				t.Errorf("addresses = %s and %s, expected %s and %s", remote, local, tt.wantRemote, tt.wantLocal)
+			}
+		})
+	}
+}
+
+func TestProxyProtocolIPFilter(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		allow      []string
+		deny       []string
+		wantServed bool
+	}{
+		{name: "load balancer allowed, client of the header denied", allow: []string{"127.0.0.1"}, deny: []string{"203.0.113.0/24"}, wantServed: true},
+		{name: "load balancer denied, client of the header allowed", allow: []string{"203.0.113.0/24"}, deny: []string{"127.0.0.1"}, wantServed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := ipfilter.New(tt.allow, tt.deny)
+			if nil != err {
+				t.Fatal(err)
+			}
+
+			pp, err := proxyproto.NewConfig("127.0.0.1")
+			if nil != err {
+				t.Fatal(err)
+			}
+
+			cfg := tcp.NewConfig()
+			cfg.IPFilter = filter
+			cfg.ProxyProtocol = pp
+			if err = cfg.SetHandler(func(conn *tcp.Conn, frame []byte) (err error) {
+				return conn.Write([]byte(conn.RemoteAddr().String()))
+			}); nil != err {
+				t.Fatal(err)
+			}
+
+			l := tcp.New()
+			if err = l.SetConfig(cfg); nil != err {
+				t.Fatal(err)
+			}
+
+			s := listenertest.Start(t, l, nil)
+
+			conn, err := net.Dial("tcp", s.Addr().String())
+			if nil != err {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+
+			conn.SetDeadline(time.Now().Add(2 * time.Second))
+
+			// the rules are checked against the load balancer as soon as it connects, before its header is read
+			io.WriteString(conn, "PROXY TCP4 203.0.113.7 198.51.100.1 5000 443\r\naddr\n")
+
+			line, err := bufio.NewReader(conn).ReadString('\n')
+			if served := nil == err; served != tt.wantServed {
+				t.Fatalf("served = %t (%q, %v), expected %t", served, line, err, tt.wantServed)
+			}
+
+			if tt.wantServed && line != "203.0.113.7:5000\n" {
+				t.Errorf("remote address = %q, expected %q", line, "203.0.113.7:5000\n")
+			}
+		})
+	}
+}
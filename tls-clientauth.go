@@ -15,7 +15,11 @@ limitations under the License.
 */
 package listener
 
-import "crypto/tls"
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+)
 
 // TLSClientAuth - TLS client authentication type
 type TLSClientAuth tls.ClientAuthType
@@ -57,3 +61,33 @@ func (tca TLSClientAuth) String() (str string) {
 
 	return tcaName[tcaInt]
 }
+
+// ParseTLSClientAuth - returns the client authentication type for the given name, as returned by `String`. The name is case insensitive
+func ParseTLSClientAuth(str string) (tca TLSClientAuth, err error) {
+
+	switch strings.ToLower(strings.TrimSpace(str)) {
+	case "", "none":
+		return TLSClientAuthNone, nil
+	case "request":
+		return TLSClientAuthRequest, nil
+	case "require":
+		return TLSClientAuthRequire, nil
+	case "verify":
+		return TLSClientAuthVerify, nil
+	case "requireverify":
+		return TLSClientAuthRequireVerify, nil
+	}
+
+	return TLSClientAuthNone, fmt.Errorf("parse client auth: unknown type '%s', must be one of none, request, require, verify or requireverify", str)
+}
+
+// MarshalText - encodes the client authentication type as its name
+func (tca TLSClientAuth) MarshalText() (text []byte, err error) {
+	return []byte(tca.String()), nil
+}
+
+// UnmarshalText - decodes the client authentication type from its name, allowing it to be used in configuration files
+func (tca *TLSClientAuth) UnmarshalText(text []byte) (err error) {
+	*tca, err = ParseTLSClientAuth(string(text))
+	return
+}
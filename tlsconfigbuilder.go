@@ -19,6 +19,7 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -176,7 +177,7 @@ func (t *TLSConfigBuilder) SetClientAuth(auth TLSClientAuth) {
 	t.clientAuth = auth
 }
 
-// ForServer - returns a configured *tls.Config for server usage.
+// ForServer - returns a configured *tls.Config for server usage. The certificate is resolved on every handshake so rotated certificates take effect immediately.
 func (t *TLSConfigBuilder) ForServer() *tls.Config {
 	tlsCfg := &tls.Config{
 		ClientAuth: t.clientAuth.AuthType(),
@@ -205,12 +206,21 @@ func (t *TLSConfigBuilder) injectServerCert(cfg *tls.Config) {
 			panic(fmt.Errorf("server cert load error: %w", err))
 		}
 	}
-	if cert, ok := t.cert.Load().(*tls.Certificate); ok {
-		cfg.Certificates = []tls.Certificate{*cert}
+	if _, ok := t.cert.Load().(*tls.Certificate); ok {
+		cfg.GetCertificate = t.getCertificate
 		t.startWatcher()
 	}
 }
 
+// getCertificate - returns the most recently loaded certificate for each TLS handshake
+func (t *TLSConfigBuilder) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	cert, ok := t.cert.Load().(*tls.Certificate)
+	if !ok {
+		return nil, errors.New("no server certificate loaded")
+	}
+	return cert, nil
+}
+
 // injectClientCert - sets a static client certificate if configured.
 func (t *TLSConfigBuilder) injectClientCert(cfg *tls.Config) {
 	if cert, ok := t.cert.Load().(*tls.Certificate); ok {
@@ -218,6 +228,17 @@ func (t *TLSConfigBuilder) injectClientCert(cfg *tls.Config) {
 	}
 }
 
+// Reload - reloads the certificate and key from the configured files, useful when the files are replaced without generating filesystem events (e.g. on SIGHUP).
+func (t *TLSConfigBuilder) Reload() error {
+	if t.certFile == "" || t.keyFile == "" {
+		return nil // certificate was set from memory or not at all, nothing to reload
+	}
+	if err := t.reloadCert(); err != nil {
+		return fmt.Errorf("reload cert '%s': %w", t.certFile, err)
+	}
+	return nil
+}
+
 // reloadCert - loads the TLS certificate from configured cert and key files.
 func (t *TLSConfigBuilder) reloadCert() error {
 	cert, err := tls.LoadX509KeyPair(t.certFile, t.keyFile)
@@ -295,3 +316,19 @@ func (t *TLSConfigBuilder) VerifyCertTrusted(certPEM []byte) error {
 	}
 	return nil
 }
+
+// Certificate - returns the certificate currently served, nil if none is loaded. Useful to monitor the expiry of rotated certificates.
+func (t *TLSConfigBuilder) Certificate() (*x509.Certificate, error) {
+	cert, ok := t.cert.Load().(*tls.Certificate)
+	if !ok || len(cert.Certificate) == 0 {
+		return nil, nil
+	}
+	if cert.Leaf != nil {
+		return cert.Leaf, nil
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("parse cert: %w", err)
+	}
+	return leaf, nil
+}
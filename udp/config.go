@@ -0,0 +1,69 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package udp
+
+import (
+	"errors"
+	"runtime"
+	"time"
+)
+
+// DefaultRateSources - source addresses tracked by the rate limiter, a few MiB of memory once all are tracked
+const DefaultRateSources = 65536
+
+// Config - listener specific configuration
+type Config struct {
+	BufferSize      int           // largest datagram read, in bytes. Larger datagrams are truncated
+	Workers         int           // number of packets handled concurrently
+	QueueSize       int           // packets waiting for a free worker, packets received while the queue is full are dropped
+	RateLimit       float64       // packets per second accepted from each source address, 0 disables rate limiting
+	RateBurst       int           // packets accepted from a source address in a single burst above the rate limit
+	RateSources     int           // source addresses tracked by the rate limiter, packets from new sources are dropped while this many are tracked
+	ShutdownTimeout time.Duration // maximum time to wait for queued packets to be handled when the listener is stopped
+	handler         HandlerFunc
+}
+
+// NewConfig - creates new instance of config
+func NewConfig() (cfg *Config) {
+	cfg = new(Config)
+
+	// set default configuration
+	cfg.BufferSize = 64 * 1024 // large enough for any UDP datagram
+	cfg.Workers = runtime.NumCPU()
+	cfg.QueueSize = 1024
+	cfg.RateLimit = 0 // no rate limiting by default
+	cfg.RateBurst = 0
+	cfg.RateSources = DefaultRateSources
+	cfg.ShutdownTimeout = time.Duration(30 * time.Second)
+	cfg.handler = nil // default create a nil instance of handler for error checking
+
+	return
+}
+
+// SetHandler - sets the function to run for every packet, with optional middlewares
+func (cfg *Config) SetHandler(hFn HandlerFunc, middlewares ...Middleware) (err error) {
+
+	if nil == hFn {
+		return errors.New("UDP sethandler: handler function cannot be nil")
+	}
+
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		hFn = middlewares[i](hFn)
+	}
+
+	cfg.handler = hFn
+	return
+}
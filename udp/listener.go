@@ -0,0 +1,463 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package udp
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/handletec/listener/describe"
+	"github.com/handletec/listener/lifecycle"
+	"github.com/handletec/listener/socket"
+	slogformatter "github.com/samber/slog-formatter"
+)
+
+const (
+	// DefaultAddr - listen on all IPv4 and IPv6 interfaces
+	DefaultAddr = "[::]"
+
+	// DefaultPort - default port to listen on
+	DefaultPort = 9000
+
+	// EphemeralPort - let the OS pick any available port, use `Addr` to find the port that was bound
+	EphemeralPort = -1
+)
+
+// Listener - implementation of UDP listener, passing every datagram to the configured handler through a pool of workers
+type Listener struct {
+	lifecycle.Lifecycle // state, hooks and readiness, reported through `Status`, `Subscribe` and `Ready`
+
+	name       string
+	address    string
+	port       int
+	tlsConfig  *tls.Config
+	logger     *slog.Logger
+	config     *Config
+	pc         net.PacketConn
+	addr       net.Addr
+	cancel     context.CancelFunc // cancels the context passed to handlers
+	readerDone chan struct{}      // closed once the reader stops and the queue is closed
+	workers    sync.WaitGroup     // tracks running workers
+	closing    atomic.Bool
+	stats      counters
+	mu         sync.Mutex // protects pc, addr, cancel and readerDone
+}
+
+// New - create new instance of the UDP listener
+func New() (l *Listener) {
+	l = new(Listener)
+	return
+}
+
+// Name - returns the name of this listener
+func (l *Listener) Name() (str string) {
+	if len(l.name) == 0 {
+		return "UDP"
+	}
+
+	return l.name
+}
+
+// SetName - sets a custom name for this listener, used in logs and to match sockets passed through socket activation
+func (l *Listener) SetName(name string) {
+	l.name = name
+}
+
+// Init - initializes this listener with any necessary configuration parameters, TLS is not supported over UDP and must be nil
+func (l *Listener) Init(logger *slog.Logger, address string, port int, tlsConfig *tls.Config) (err error) {
+
+	defer func() {
+		if nil != err {
+			l.SetState(l.Name(), lifecycle.Failed, err)
+			return
+		}
+		l.SetState(l.Name(), lifecycle.Initialized, nil)
+	}()
+
+	if len(address) == 0 {
+		address = DefaultAddr // if no address is given we have it listen on all IPv4 and IPv6 interfaces
+	}
+
+	switch {
+	case port == 0:
+		port = DefaultPort // default port if none is given
+	case port == EphemeralPort:
+		port = 0 // the OS assigns a free port when binding to port 0
+	case port < 0 || port > 65535:
+		return fmt.Errorf("UDP init: invalid port %d", port)
+	}
+
+	if nil != tlsConfig {
+		return errors.New("UDP init: TLS is not supported, DTLS is not implemented")
+	}
+
+	l.address = address
+	l.port = port
+
+	l.mu.Lock()
+	l.addr = nil
+	l.mu.Unlock()
+
+	if nil == logger {
+		// if no logger is given, create a new instance
+		logger = slog.New(
+			slogformatter.NewFormatterHandler(
+				slogformatter.TimezoneConverter(time.UTC),
+				slogformatter.TimeFormatter(time.RFC3339, nil),
+			)(
+				slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{}),
+			),
+		)
+	}
+
+	l.logger = logger
+
+	if nil == l.config {
+		// if no configuration is set, init a new one with sane default values
+		l.config = NewConfig()
+	}
+
+	if err = l.Hooks().Run(context.Background(), lifecycle.EventInit); nil != err {
+		return fmt.Errorf("UDP init: %w", err)
+	}
+
+	return
+}
+
+// SetConfig - sets configuration details for this listener
+func (l *Listener) SetConfig(config any) (err error) {
+	cfg, ok := config.(*Config)
+	if !ok {
+		return fmt.Errorf("UDP setconfig: expected *udp.Config, received %T", config)
+	}
+
+	l.config = cfg
+	return
+}
+
+// Config - returns the configuration in use by this listener
+func (l *Listener) Config() (config any) {
+	return l.config
+}
+
+// Start - starts this listener and blocks until it stops, cancelling ctx gracefully shuts down the listener
+func (l *Listener) Start(ctx context.Context) (err error) {
+	l.logger.Info("listener starting", "listener", l.Name())
+	l.SetState(l.Name(), lifecycle.Starting, nil)
+
+	defer func() {
+		if nil != err {
+			l.SetState(l.Name(), lifecycle.Failed, err)
+		}
+	}()
+
+	if nil == l.config.handler {
+		return errors.New("UDP start: no handler configured")
+	}
+
+	if l.config.BufferSize <= 0 {
+		return fmt.Errorf("UDP start: invalid buffer size %d", l.config.BufferSize)
+	}
+
+	if err = l.Hooks().Run(ctx, lifecycle.EventBeforeStart); nil != err {
+		return fmt.Errorf("start udp: %w", err)
+	}
+
+	pc, err := l.listen()
+	if nil != err {
+		return fmt.Errorf("start udp: %w", err)
+	}
+
+	// allow the socket to be passed to a new process during a binary upgrade
+	socket.TrackPacketConn(l.Name(), pc)
+	defer socket.UntrackPacketConn(pc)
+
+	l.closing.Store(false)
+
+	queueSize := l.config.QueueSize
+	if queueSize < 0 {
+		queueSize = 0
+	}
+	queue := make(chan *Packet, queueSize)
+
+	handlerCtx, cancel := context.WithCancel(context.Background())
+
+	workers := l.config.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	for i := 0; i < workers; i++ {
+		l.workers.Add(1)
+		go l.work(handlerCtx, queue)
+	}
+
+	readerDone := make(chan struct{})
+
+	l.mu.Lock()
+	l.pc = pc
+	l.addr = pc.LocalAddr()
+	l.cancel = cancel
+	l.readerDone = readerDone
+	l.mu.Unlock()
+
+	l.logger.Info("listener started", "listener", l.Name(), "address", "udp://"+pc.LocalAddr().String(), "workers", workers)
+
+	readErr := make(chan error, 1)
+	go func() {
+		readErr <- l.read(pc, queue)
+		close(queue) // the reader is the only sender, workers exit once the queue is drained
+		close(readerDone)
+	}()
+
+	if err = l.Started(ctx, l.Name()); nil != err {
+		// a started hook failed, stop reading datagrams and wait for the workers so no datagram is handled half set up
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), l.config.ShutdownTimeout)
+		defer cancel()
+
+		l.Shutdown(shutdownCtx)
+		<-readErr
+
+		return fmt.Errorf("start udp: %w", err)
+	}
+
+	select {
+	case err = <-readErr:
+		// reading failed, unless the listener was shutdown the socket and workers still need to be stopped
+		l.mu.Lock()
+		running := l.pc
+		l.pc = nil
+		l.mu.Unlock()
+
+		if nil != running {
+			l.workers.Wait()
+			cancel()
+			running.Close()
+
+			// reading failed, e.g. the socket was closed underneath it, the stopped hooks still run to release what the started hooks acquired
+			err = errors.Join(err, l.Hooks().Run(context.WithoutCancel(ctx), lifecycle.EventStopped))
+		}
+	case <-ctx.Done():
+		// the caller no longer wants this listener running, drain queued packets before returning
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), l.config.ShutdownTimeout)
+		defer cancel()
+
+		err = l.Shutdown(shutdownCtx)
+		<-readErr
+	}
+
+	if nil != err {
+		return fmt.Errorf("start udp: %w", err)
+	}
+
+	return nil
+}
+
+// read - reads datagrams and queues them for the workers until the listener is closed
+func (l *Listener) read(pc net.PacketConn, queue chan<- *Packet) (err error) {
+
+	maxSources := l.config.RateSources
+	if maxSources <= 0 {
+		maxSources = DefaultRateSources
+	}
+
+	lim := newLimiter(l.config.RateLimit, l.config.RateBurst, maxSources)
+	buf := make([]byte, l.config.BufferSize)
+
+	var backoff time.Duration
+
+	for {
+		n, src, err := pc.ReadFrom(buf)
+		if nil != err {
+			if l.isClosing() {
+				return nil
+			}
+
+			if errors.Is(err, net.ErrClosed) {
+				return err
+			}
+
+			// some platforms report errors such as ICMP port unreachable for earlier replies, these do not stop the socket from reading
+			l.logger.Debug("packet read failed", "listener", l.Name(), "error", err)
+
+			if backoff == 0 {
+				backoff = 5 * time.Millisecond
+			} else if backoff *= 2; backoff > time.Second {
+				backoff = time.Second
+			}
+			time.Sleep(backoff)
+			continue
+		}
+
+		backoff = 0
+
+		now := time.Now()
+		l.stats.received.Add(1)
+
+		if !lim.allow(src, now) {
+			l.stats.droppedRateLimited.Add(1)
+			l.logger.Debug("packet dropped, rate limit exceeded", "listener", l.Name(), "source", src.String())
+			continue
+		}
+
+		pkt := &Packet{
+			Data:     append([]byte(nil), buf[:n]...), // the read buffer is reused for the next datagram
+			Source:   src,
+			Received: now,
+			pc:       pc,
+		}
+
+		select {
+		case queue <- pkt:
+		default:
+			l.stats.droppedQueueFull.Add(1)
+			l.logger.Debug("packet dropped, queue full", "listener", l.Name(), "source", src.String())
+		}
+	}
+}
+
+// work - runs the handler for queued packets until the queue is closed
+func (l *Listener) work(ctx context.Context, queue <-chan *Packet) {
+	defer l.workers.Done()
+
+	for pkt := range queue {
+		if nil != ctx.Err() {
+			l.stats.droppedShutdown.Add(1) // shutdown timed out, discard what is left in the queue
+			continue
+		}
+
+		if err := l.config.handler(ctx, pkt); nil != err {
+			l.stats.failed.Add(1)
+			l.logger.Debug("packet handler failed", "listener", l.Name(), "source", pkt.Source.String(), "error", err)
+			continue
+		}
+
+		l.stats.handled.Add(1)
+	}
+}
+
+// listen - uses the socket passed by the supervisor under this listener's name if there is one, otherwise binds to the configured address and port
+func (l *Listener) listen() (pc net.PacketConn, err error) {
+
+	pc, err = socket.PacketConn(l.Name())
+	if nil != err {
+		return nil, err
+	}
+
+	if nil != pc {
+		l.logger.Info("listener using activated socket", "listener", l.Name(), "address", pc.LocalAddr().String())
+		return
+	}
+
+	address := net.JoinHostPort(strings.Trim(l.address, "[]"), strconv.Itoa(l.port))
+
+	return net.ListenPacket("udp", address)
+}
+
+// Shutdown - stops reading new packets and waits for queued packets to be handled or for ctx to expire, whichever comes first
+func (l *Listener) Shutdown(ctx context.Context) (err error) {
+
+	l.mu.Lock()
+	pc := l.pc
+	l.pc = nil
+	cancel := l.cancel
+	readerDone := l.readerDone
+	l.mu.Unlock()
+
+	if nil == pc {
+		return // listener was never started, nothing to shutdown
+	}
+
+	l.logger.Info("listener shutting down", "listener", l.Name())
+	l.SetState(l.Name(), lifecycle.Draining, nil)
+
+	// deregister from e.g. service discovery while datagrams are still read, so senders switch to another instance
+	hookErr := l.Hooks().Run(ctx, lifecycle.EventShutdown)
+
+	// interrupt the reader but keep the socket open so handlers are still able to reply
+	l.closing.Store(true)
+	pc.SetReadDeadline(time.Now())
+
+	done := make(chan struct{})
+	go func() {
+		<-readerDone
+		l.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		cancel() // tell running handlers to give up, remaining queued packets are dropped
+		<-done
+		err = fmt.Errorf("shutdown udp: %w", ctx.Err())
+	}
+
+	cancel()
+	pc.Close()
+
+	l.logger.Info("listener stopped", "listener", l.Name(), "dropped", l.stats.snapshot().Dropped())
+
+	// workers finished the datagrams already read or the drain timed out, stopped hooks run either way
+	err = l.Stopped(ctx, l.Name(), errors.Join(err, hookErr))
+
+	return
+}
+
+// Stats - returns the packet counters of this listener, including the number of dropped packets
+func (l *Listener) Stats() (stats Stats) {
+	return l.stats.snapshot()
+}
+
+// Describe - describes the address of this listener, TLS is not supported over UDP
+func (l *Listener) Describe() (d describe.Description) {
+	d.Name = l.Name()
+	d.Protocol = "UDP"
+	d.Address = describe.Address(l.Addr(), l.address, l.port)
+
+	return
+}
+
+// Addr - returns the address this listener is bound to, or nil if it has not been started
+func (l *Listener) Addr() (addr net.Addr) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.addr
+}
+
+// Status - returns the lifecycle state of this UDP listener under its current name, which may have been changed by `SetName`
+func (l *Listener) Status() (status lifecycle.Status) {
+	status = l.Lifecycle.Status()
+	status.Name = l.Name()
+
+	return
+}
+
+// isClosing - determines if the listener is shutting down
+func (l *Listener) isClosing() (closing bool) {
+	return l.closing.Load()
+}
@@ -0,0 +1,42 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package udp
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+// HandlerFunc - function run for every packet received, errors are logged as there is no connection to close
+type HandlerFunc func(ctx context.Context, pkt *Packet) (err error)
+
+// Middleware - wraps a handler to run common tasks such as validation or logging before the handler is called
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// Packet - single datagram received by the listener
+type Packet struct {
+	Data     []byte    // payload of the datagram, owned by the handler
+	Source   net.Addr  // address the datagram was sent from
+	Received time.Time // time the datagram was read from the socket
+	pc       net.PacketConn
+}
+
+// Reply - sends a datagram back to the source address of this packet, from the address the listener is bound to
+func (pkt *Packet) Reply(data []byte) (err error) {
+	_, err = pkt.pc.WriteTo(data, pkt.Source)
+	return
+}
@@ -0,0 +1,108 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package udp
+
+import (
+	"math"
+	"net"
+	"sync"
+	"time"
+)
+
+// limiterSweep - how often buckets that filled up again are discarded
+const limiterSweep = time.Second
+
+// bucket - token bucket of a single source address
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// limiter - per source token bucket rate limiter
+type limiter struct {
+	rate    float64       // tokens added every second
+	burst   float64       // maximum tokens held by a bucket
+	idle    time.Duration // time an unused bucket takes to fill up, after which it is no different from a new one
+	max     int           // maximum number of buckets, packets from new sources are dropped once reached
+	buckets map[string]*bucket
+	swept   time.Time // last time idle buckets were discarded
+	mu      sync.Mutex
+}
+
+// newLimiter - create new instance of limiter tracking up to maxSources source addresses, nil if rate limiting is disabled
+func newLimiter(rate float64, burst int, maxSources int) (lim *limiter) {
+
+	if rate <= 0 {
+		return nil
+	}
+
+	lim = new(limiter)
+	lim.rate = rate
+	lim.burst = math.Max(float64(burst), 1) // a single packet must always be allowed through
+	lim.idle = time.Duration(lim.burst / rate * float64(time.Second))
+	lim.max = maxSources
+	lim.buckets = make(map[string]*bucket)
+	lim.swept = time.Now()
+
+	return
+}
+
+// allow - determines if a packet from the source is within its rate limit, sources are identified by IP address only
+func (lim *limiter) allow(source net.Addr, now time.Time) (allowed bool) {
+
+	if nil == lim {
+		return true
+	}
+
+	key := source.String()
+	if ua, ok := source.(*net.UDPAddr); ok {
+		key = ua.IP.String()
+	}
+
+	lim.mu.Lock()
+	defer lim.mu.Unlock()
+
+	if now.Sub(lim.swept) >= limiterSweep {
+		// a bucket unused long enough to fill up is recreated identical when its source is seen again, so dropping it changes nothing
+		for k, b := range lim.buckets {
+			if now.Sub(b.last) >= lim.idle {
+				delete(lim.buckets, k)
+			}
+		}
+		lim.swept = now
+	}
+
+	b, ok := lim.buckets[key]
+	if !ok {
+		if len(lim.buckets) >= lim.max {
+			// a flood from spoofed addresses must not grow memory without bound, sources already tracked keep being served
+			return false
+		}
+
+		b = &bucket{tokens: lim.burst, last: now}
+		lim.buckets[key] = b
+	}
+
+	b.tokens = math.Min(lim.burst, b.tokens+now.Sub(b.last).Seconds()*lim.rate)
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+
+	b.tokens--
+	return true
+}
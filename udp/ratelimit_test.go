@@ -0,0 +1,108 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package udp
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestLimiter(t *testing.T) {
+
+	start := time.Now()
+	addr := func(ip string) net.Addr {
+		return &net.UDPAddr{IP: net.ParseIP(ip), Port: 5000}
+	}
+
+	type packet struct {
+		source  string
+		after   time.Duration // time since start
+		allowed bool
+	}
+
+	tests := []struct {
+		name       string
+		rate       float64
+		burst      int
+		maxSources int
+		packets    []packet
+	}{
+		{
+			name: "burst then limited", rate: 1, burst: 2, maxSources: 10,
+			packets: []packet{
+				{"192.0.2.1", 0, true},
+				{"192.0.2.1", 0, true},
+				{"192.0.2.1", 0, false},
+				{"192.0.2.1", time.Second, true}, // one token refilled
+			},
+		},
+		{
+			name: "sources are limited separately, ports ignored", rate: 1, burst: 1, maxSources: 10,
+			packets: []packet{
+				{"192.0.2.1", 0, true},
+				{"192.0.2.2", 0, true},
+				{"192.0.2.1", 0, false},
+			},
+		},
+		{
+			name: "new sources dropped once full", rate: 1, burst: 1, maxSources: 2,
+			packets: []packet{
+				{"192.0.2.1", 0, true},
+				{"192.0.2.2", 0, true},
+				{"192.0.2.3", 0, false},
+				{"192.0.2.1", 2 * time.Second, true}, // tracked sources are still served
+			},
+		},
+		{
+			name: "refilled buckets are swept, freeing room", rate: 10, burst: 1, maxSources: 1,
+			packets: []packet{
+				{"192.0.2.1", 0, true},
+				{"192.0.2.2", 500 * time.Millisecond, false},
+				{"192.0.2.2", limiterSweep, true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lim := newLimiter(tt.rate, tt.burst, tt.maxSources)
+			lim.swept = start
+
+			for i, p := range tt.packets {
+				if got := lim.allow(addr(p.source), start.Add(p.after)); got != p.allowed {
+					t.Fatalf("packet %d from %s after %s: allowed %t, expected %t", i, p.source, p.after, got, p.allowed)
+				}
+			}
+
+			if len(lim.buckets) > tt.maxSources {
+				t.Fatalf("%d buckets tracked, limit is %d", len(lim.buckets), tt.maxSources)
+			}
+		})
+	}
+}
+
+func TestLimiterDisabled(t *testing.T) {
+
+	lim := newLimiter(0, 10, 10)
+	if nil != lim {
+		t.Fatal("a rate of 0 must disable the limiter")
+	}
+
+	if !lim.allow(&net.UDPAddr{IP: net.IPv4(192, 0, 2, 1)}, time.Now()) {
+		t.Fatal("a disabled limiter must allow every packet")
+	}
+}
@@ -0,0 +1,57 @@
+/*
+Copyright © 2025 Vicknesh Suppramaniam <vicknesh@handletec.my>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package udp
+
+import (
+	"sync/atomic"
+)
+
+// Stats - packet counters of a listener since it was created
+type Stats struct {
+	Received           uint64 // packets read from the socket
+	Handled            uint64 // packets handled without error
+	Failed             uint64 // packets where the handler returned an error
+	DroppedRateLimited uint64 // packets dropped as the source exceeded its rate limit
+	DroppedQueueFull   uint64 // packets dropped as all workers were busy and the queue was full
+	DroppedShutdown    uint64 // queued packets dropped as the listener did not drain them before the shutdown timeout
+}
+
+// Dropped - returns the total number of packets dropped without being handled
+func (s Stats) Dropped() (dropped uint64) {
+	return s.DroppedRateLimited + s.DroppedQueueFull + s.DroppedShutdown
+}
+
+// counters - live packet counters, updated concurrently by the reader and workers
+type counters struct {
+	received           atomic.Uint64
+	handled            atomic.Uint64
+	failed             atomic.Uint64
+	droppedRateLimited atomic.Uint64
+	droppedQueueFull   atomic.Uint64
+	droppedShutdown    atomic.Uint64
+}
+
+// snapshot - returns the current value of all counters
+func (c *counters) snapshot() (s Stats) {
+	s.Received = c.received.Load()
+	s.Handled = c.handled.Load()
+	s.Failed = c.failed.Load()
+	s.DroppedRateLimited = c.droppedRateLimited.Load()
+	s.DroppedQueueFull = c.droppedQueueFull.Load()
+	s.DroppedShutdown = c.droppedShutdown.Load()
+
+	return
+}